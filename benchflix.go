@@ -2,9 +2,11 @@ package benchflix
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -17,13 +19,13 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ory/dockertest"
 	"github.com/ory/dockertest/docker"
-	"golang.org/x/tools/benchmark/parse"
 )
 
 var (
-	ErrSkip = errors.New("skip")
-	Pool    = Must(dockertest.NewPool(""))
-	Movies  []Movie
+	ErrSkip     = errors.New("skip")
+	ErrNotFound = errors.New("not found")
+	Pool        = Must(dockertest.NewPool(""))
+	Movies      []Movie
 )
 
 type Movie struct {
@@ -32,6 +34,22 @@ type Movie struct {
 	AddedAt   time.Time `db:"added_at"`
 	Rating    float64
 	Directors []string
+	Runtime   *int64
+	Budget    *int64
+	Tagline   *string
+	Metadata  *Metadata
+}
+
+type Metadata struct {
+	Language   string      `json:"language"`
+	Genres     []string    `json:"genres"`
+	Popularity float64     `json:"popularity"`
+	Collection *Collection `json:"collection,omitempty"`
+}
+
+type Collection struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
 }
 
 type ListParams struct {
@@ -51,11 +69,166 @@ type DashboardParams struct {
 	WithDirectors bool `json:"with_directors"`
 }
 
+type SearchParams struct {
+	Search    string
+	Websearch bool `json:"websearch"`
+	Limit     uint64
+}
+
+type SearchResult struct {
+	Movie
+	Rank     float64
+	Headline string
+}
+
+type Facet struct {
+	Value int64
+	Count int64
+}
+
+type FacetResult struct {
+	Movies  []Movie
+	Years   []Facet
+	Ratings []Facet
+}
+
+type TopParams struct {
+	MinRating float64 `db:"min_rating" json:"min_rating"`
+	PerYear   uint64  `db:"per_year" json:"per_year"`
+}
+
+type TopMovie struct {
+	Movie
+	Year     int64
+	Position int64
+	// average rating over all movies of each director, in the order of Directors
+	DirectorRatings []float64 `json:"director_ratings"`
+}
+
+type GraphParams struct {
+	Director string
+	Depth    uint64
+}
+
+type Collaborator struct {
+	ID    int64
+	Name  string
+	Depth int64
+	// titles of the co-directed movies along the shortest path from the start
+	Movies []string
+}
+
+type WideParams struct {
+	After int64
+	Limit uint64
+}
+
+type WideRow struct {
+	ID     int64
+	Int01  int64
+	Int02  int64
+	Int03  int64
+	Int04  int64
+	Int05  int64
+	Int06  int64
+	Int07  int64
+	Int08  int64
+	Int09  int64
+	Int10  int64
+	Int11  int64
+	Int12  int64
+	Num01  float64
+	Num02  float64
+	Num03  float64
+	Num04  float64
+	Num05  float64
+	Num06  float64
+	Num07  float64
+	Num08  float64
+	Num09  float64
+	Num10  float64
+	Num11  float64
+	Num12  float64
+	Time01 time.Time
+	Time02 time.Time
+	Time03 time.Time
+	Time04 time.Time
+	Time05 time.Time
+	Time06 time.Time
+	Time07 time.Time
+	Time08 time.Time
+	Time09 time.Time
+	Time10 time.Time
+	Time11 time.Time
+	Time12 time.Time
+	Tags01 []string
+	Tags02 []string
+	Tags03 []string
+	Tags04 []string
+	Tags05 []string
+	Tags06 []string
+	Tags07 []string
+	Tags08 []string
+	Tags09 []string
+	Tags10 []string
+	Tags11 []string
+	Tags12 []string
+	Note01 *string
+	Note02 *string
+	Note03 *string
+	Note04 *string
+	Note05 *string
+	Note06 *string
+	Note07 *string
+	Note08 *string
+	Note09 *string
+	Note10 *string
+	Note11 *string
+	Note12 *string
+}
+
+type PreloadStrategy string
+
+const (
+	PreloadAny       PreloadStrategy = "any"
+	PreloadUnnest    PreloadStrategy = "unnest"
+	PreloadTempTable PreloadStrategy = "temp_table"
+	PreloadBatch     PreloadStrategy = "batch"
+)
+
+type PreloadParams struct {
+	Strategy PreloadStrategy
+	IDs      []int64
+}
+
+// Person is a director as decoded from a json_build_object.
+type Person struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type MovieDirectors struct {
+	MovieID   int64
+	Directors []string
+}
+
 type Repository interface {
 	QueryList(ctx context.Context, params ListParams) ([]Movie, error)
 	QueryListPreload(ctx context.Context, params ListParams) ([]Movie, error)
+	QueryListNPlusOne(ctx context.Context, params ListParams) ([]Movie, error)
+	QueryListJSON(ctx context.Context, params ListParams) ([]Movie, error)
 	QueryDashboard(ctx context.Context, params DashboardParams) ([]Movie, error)
 	QueryDashboardPreload(ctx context.Context, params DashboardParams) ([]Movie, error)
+	QueryDetails(ctx context.Context, params ListParams) ([]Movie, error)
+	QueryDetailsNull(ctx context.Context, params ListParams) ([]Movie, error)
+	QuerySearch(ctx context.Context, params SearchParams) ([]SearchResult, error)
+	QueryFacets(ctx context.Context, params DashboardParams) (FacetResult, error)
+	QueryFacetsBatch(ctx context.Context, params DashboardParams) (FacetResult, error)
+	QueryTopRated(ctx context.Context, params TopParams) ([]TopMovie, error)
+	QueryCollaborators(ctx context.Context, params GraphParams) ([]Collaborator, error)
+	QueryMovie(ctx context.Context, id int64) (Movie, error)
+	QueryWide(ctx context.Context, params WideParams) ([]WideRow, error)
+	QueryDirectors(ctx context.Context, params PreloadParams) ([]MovieDirectors, error)
 }
 
 func Must[T any](t T, err error) T {
@@ -87,26 +260,109 @@ func init() {
 			Rating:    Must(strconv.ParseFloat(record[8], 64)),
 			Directors: strings.Split(record[3], ", "),
 		}
+
+		details(&Movies[i])
 	}
 }
 
+var (
+	languages = []string{"en", "fr", "de", "ja", "es", "ko"}
+	genres    = []string{"Action", "Comedy", "Drama", "Horror", "Romance", "Thriller", "Animation", "Documentary"}
+)
+
+// details derives the nullable columns from the movie ID, since movies.csv does not
+// carry them. Every column is NULL for a different share of the rows.
+func details(movie *Movie) {
+	if movie.ID%3 != 0 {
+		runtime := 75 + movie.ID%105
+		movie.Runtime = &runtime
+	}
+
+	if movie.ID%4 != 0 {
+		budget := (1 + movie.ID%250) * 1_000_000
+		movie.Budget = &budget
+	}
+
+	if movie.ID%5 != 0 {
+		tagline := fmt.Sprintf("%s, directed by %s.", movie.Title, strings.Join(movie.Directors, " and "))
+		movie.Tagline = &tagline
+	}
+
+	if movie.ID%2 != 0 {
+		return
+	}
+
+	movie.Metadata = &Metadata{
+		Language:   languages[movie.ID%int64(len(languages))],
+		Genres:     []string{genres[movie.ID%int64(len(genres))], genres[(movie.ID/7)%int64(len(genres))]},
+		Popularity: float64(movie.ID%10_000) / 100,
+	}
+
+	if movie.ID%6 == 0 {
+		movie.Metadata.Collection = &Collection{
+			ID:   movie.ID / 6,
+			Name: movie.Title + " Collection",
+		}
+	}
+}
+
+// ChangesChannel is notified with the table name by every statement writing to
+// movies, people or movie_directors.
+const ChangesChannel = "benchflix_changes"
+
 func InitializePostgres(name string) (string, *dockertest.Resource) {
-	resource := dockerPostgres(name)
+	conn, resource, err := StartPostgres(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return conn, resource
+}
+
+// StartPostgres is InitializePostgres returning errors. The container is
+// removed again if loading fails.
+func StartPostgres(name string) (string, *dockertest.Resource, error) {
+	resource, err := dockerPostgres(name)
+	if err != nil {
+		return "", nil, err
+	}
 
 	conn := fmt.Sprintf("host=localhost port=%s user=user password=password dbname=db sslmode=disable timezone=UTC", resource.GetPort("5432/tcp"))
 
-	cfg := Must(pgxpool.ParseConfig(conn))
+	if err = loadPostgres(conn); err != nil {
+		_ = resource.Close()
+
+		return "", nil, err
+	}
+
+	return conn, resource, nil
+}
 
-	db := Must(pgxpool.NewWithConfig(context.Background(), cfg))
+func loadPostgres(conn string) error {
+	cfg, err := pgxpool.ParseConfig(conn)
+	if err != nil {
+		return err
+	}
+
+	db, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
 
 	defer db.Close()
 
-	_ = Must(db.Exec(context.Background(), `
+	if _, err = db.Exec(context.Background(), `
+		CREATE EXTENSION IF NOT EXISTS pg_stat_statements;
+
 		CREATE TABLE IF NOT EXISTS movies (
 			id INTEGER PRIMARY KEY
 			, title TEXT NOT NULL
 			, added_at DATE NOT NULL
 			, rating NUMERIC NOT NULL
+			, runtime INTEGER
+			, budget BIGINT
+			, tagline TEXT
+			, metadata JSONB
 		);
 
 		CREATE TABLE IF NOT EXISTS people (
@@ -127,23 +383,83 @@ func InitializePostgres(name string) (string, *dockertest.Resource) {
 		CREATE INDEX IF NOT EXISTS idx_movies_rating ON movies (rating);
 		CREATE INDEX IF NOT EXISTS idx_movies_title ON movies (title);
 		CREATE INDEX IF NOT EXISTS idx_md_movie_person ON movie_directors (movie_id, person_id);
-	`))
+	`); err != nil {
+		return err
+	}
 
 	for _, movie := range Movies {
-		insertPostgres(context.Background(), db, movie)
+		if err = insertPostgres(context.Background(), db, movie); err != nil {
+			return fmt.Errorf("movie %d: %w", movie.ID, err)
+		}
 	}
 
-	return conn, resource
+	if _, err = db.Exec(context.Background(), wideTable(12, 10_000)); err != nil {
+		return err
+	}
+
+	// created after loading, so that the inserts above notify no one
+	_, err = db.Exec(context.Background(), `
+		CREATE OR REPLACE FUNCTION notify_changes() RETURNS trigger AS $$
+		BEGIN
+			PERFORM pg_notify('`+ChangesChannel+`', TG_TABLE_NAME);
+
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql;
+
+		CREATE OR REPLACE TRIGGER movies_changes AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON movies
+			FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
+		CREATE OR REPLACE TRIGGER people_changes AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON people
+			FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
+		CREATE OR REPLACE TRIGGER movie_directors_changes AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON movie_directors
+			FOR EACH STATEMENT EXECUTE FUNCTION notify_changes();
+	`)
+
+	return err
+}
+
+// wideTable creates the wide_rows table with n columns per type, in the field order of
+// WideRow, and fills it with generated rows. Every note column is NULL for a different
+// third of the rows.
+func wideTable(n, rows int) string {
+	var columns, values strings.Builder
+
+	for _, typ := range []struct{ name, ddl, value string }{
+		{"int", "BIGINT NOT NULL", "g * %d"},
+		{"num", "NUMERIC NOT NULL", "(g %% 1000) / 10.0 + %d"},
+		{"time", "TIMESTAMPTZ NOT NULL", "TIMESTAMPTZ '2000-01-01 00:00:00+00' + g * INTERVAL '1 hour' + %d * INTERVAL '1 day'"},
+		{"tags", "TEXT[] NOT NULL", "ARRAY['tag' || (g %% 7), 'tag' || ((g + %d) %% 11)]"},
+		{"note", "TEXT", "CASE WHEN (g + %[1]d) %% 3 = 0 THEN NULL ELSE 'note ' || g || '/' || %[1]d END"},
+	} {
+		for i := 1; i <= n; i++ {
+			fmt.Fprintf(&columns, "\n\t\t\t, %s%02d %s", typ.name, i, typ.ddl)
+			fmt.Fprintf(&values, "\n\t\t\t, "+typ.value, i)
+		}
+	}
+
+	return fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS wide_rows (
+			id BIGINT PRIMARY KEY%s
+		);
+
+		INSERT INTO wide_rows
+		SELECT
+			g%s
+		FROM generate_series(1, %d) AS g
+		ON CONFLICT DO NOTHING;
+	`, columns.String(), values.String(), rows)
 }
 
-func insertPostgres(ctx context.Context, pool *pgxpool.Pool, movie Movie) {
-	_ = Must(pool.Exec(ctx,
-		`INSERT INTO movies (id, title, added_at, rating) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING;`,
-		movie.ID, movie.Title, movie.AddedAt, movie.Rating,
-	))
+func insertPostgres(ctx context.Context, pool *pgxpool.Pool, movie Movie) error {
+	if _, err := pool.Exec(ctx,
+		`INSERT INTO movies (id, title, added_at, rating, runtime, budget, tagline, metadata) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT DO NOTHING;`,
+		movie.ID, movie.Title, movie.AddedAt, movie.Rating, movie.Runtime, movie.Budget, movie.Tagline, movie.Metadata,
+	); err != nil {
+		return err
+	}
 
 	if len(movie.Directors) == 0 {
-		return
+		return nil
 	}
 
 	var (
@@ -160,10 +476,13 @@ func insertPostgres(ctx context.Context, pool *pgxpool.Pool, movie Movie) {
 		sb.WriteString(fmt.Sprintf("($%d)", len(args)))
 	}
 
-	rows := Must(pool.Query(ctx,
+	rows, err := pool.Query(ctx,
 		`INSERT INTO people (name) VALUES `+sb.String()+` ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name RETURNING id;`,
 		args...,
-	))
+	)
+	if err != nil {
+		return err
+	}
 
 	defer rows.Close()
 
@@ -173,30 +492,36 @@ func insertPostgres(ctx context.Context, pool *pgxpool.Pool, movie Movie) {
 	for rows.Next() {
 		var id int64
 
-		if err := rows.Scan(&id); err != nil {
-			panic(err)
+		if err = rows.Scan(&id); err != nil {
+			return err
 		}
 
 		args = append(args, id)
 		sb.WriteString(fmt.Sprintf(",($1, $%d)", len(args)))
 	}
 
-	_ = Must(pool.Exec(ctx,
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = pool.Exec(ctx,
 		`INSERT INTO movie_directors (movie_id, person_id) VALUES `+sb.String()[1:]+" ON CONFLICT DO NOTHING;",
 		args...,
-	))
+	)
+
+	return err
 }
 
-func dockerPostgres(name string) *dockertest.Resource {
+func dockerPostgres(name string) (*dockertest.Resource, error) {
 	if err := Pool.Client.Ping(); err != nil {
-		panic(fmt.Errorf("could not connect to Docker: %s", err))
+		return nil, fmt.Errorf("could not connect to Docker: %w", err)
 	}
 
 	if err := removePostgresContainer(Pool, name); err != nil {
-		panic(fmt.Errorf("removing old container: %w", err))
+		return nil, fmt.Errorf("removing old container: %w", err)
 	}
 
-	resource := Must(Pool.RunWithOptions(&dockertest.RunOptions{
+	resource, err := Pool.RunWithOptions(&dockertest.RunOptions{
 		Name:       name,
 		Repository: "postgres",
 		Tag:        "17",
@@ -205,12 +530,20 @@ func dockerPostgres(name string) *dockertest.Resource {
 			"POSTGRES_PASSWORD=password",
 			"POSTGRES_DB=db",
 		},
+		Cmd: []string{
+			"postgres",
+			"-c", "shared_preload_libraries=pg_stat_statements",
+			"-c", "pg_stat_statements.track_planning=on",
+		},
 	}, func(config *docker.HostConfig) {
 		config.AutoRemove = true
 		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
-	}))
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if err := Pool.Retry(func() error {
+	if err = Pool.Retry(func() error {
 		db, err := sql.Open("pgx", fmt.Sprintf(
 			"host=localhost port=%s user=user password=password dbname=db sslmode=disable",
 			resource.GetPort("5432/tcp"),
@@ -224,14 +557,17 @@ func dockerPostgres(name string) *dockertest.Resource {
 	}); err != nil {
 		_ = resource.Close()
 
-		panic(fmt.Errorf("postgres never became ready: %w", err))
+		return nil, fmt.Errorf("postgres never became ready: %w", err)
 	}
 
-	return resource
+	return resource, nil
 }
 
 func removePostgresContainer(pool *dockertest.Pool, name string) error {
-	containers := Must(pool.Client.ListContainers(docker.ListContainersOptions{All: true}))
+	containers, err := pool.Client.ListContainers(docker.ListContainersOptions{All: true})
+	if err != nil {
+		return err
+	}
 
 	for _, c := range containers {
 		if slices.Contains(c.Names, "/"+name) {
@@ -247,90 +583,406 @@ func removePostgresContainer(pool *dockertest.Pool, name string) error {
 
 type Benchmark struct {
 	SQL, PGX, SQUIRREL, SQLX, GORM, SQLC, SQLT, SQLTCACHE Framework
+
+	where map[string]string
 }
 
 type Framework struct {
-	List, ListPreload, Dashboard, DashboardPreload Szenario
+	List, ListPreload, ListPreloadLoader, ListNPlusOne, ListJSON, Dashboard, DashboardPreload, DashboardPreloadLoader, Details, DetailsNull, Search, Websearch, Facets, FacetsBatch, TopRated, Collaborators, Movie, Wide Szenario
+
+	PreloadAny, PreloadUnnest, PreloadTempTable, PreloadBatch Strategy
+
+	ListPreloadCached, DashboardPreloadCached Cache
 }
 
 type Szenario struct {
 	Hundred, Thousand Params
 }
 
+type Strategy struct {
+	Ten, Thousand, TenThousand, FiftyThousand Params
+}
+
+type Cache struct {
+	Ten, Hundred, Thousand Params
+}
+
 type Params struct {
 	NsPerOp, BytesPerOp, AllocsPerOp []float64
+
+	AcquiresPerOp, EmptyAcquiresPerOp, NsPerAcquire, NewConns, ClosedConns []float64
+
+	WaitsPerOp, WaitNsPerOp []float64
+
+	WarmupCalls, WarmupMs []float64
+
+	// Metrics holds every metric by unit, also those without a field above.
+	// Labels are those of every result, parsed from its name.
+	Metrics map[string][]float64
+	Labels  []map[string]string
 }
 
+func (p *Params) append(labels map[string]string, metrics map[string]float64) {
+	if p.Metrics == nil {
+		p.Metrics = map[string][]float64{}
+	}
+
+	for unit, v := range metrics {
+		p.Metrics[unit] = append(p.Metrics[unit], v)
+	}
+
+	p.Labels = append(p.Labels, labels)
+
+	p.NsPerOp = append(p.NsPerOp, metrics["ns/op"])
+	p.BytesPerOp = append(p.BytesPerOp, metrics["B/op"])
+	p.AllocsPerOp = append(p.AllocsPerOp, metrics["allocs/op"])
+
+	for unit, values := range map[string]*[]float64{
+		"acquires/op":       &p.AcquiresPerOp,
+		"empty-acquires/op": &p.EmptyAcquiresPerOp,
+		"ns/acquire":        &p.NsPerAcquire,
+		"waits/op":          &p.WaitsPerOp,
+		"wait-ns/op":        &p.WaitNsPerOp,
+		"new-conns":         &p.NewConns,
+		"closed-conns":      &p.ClosedConns,
+		"warmup-calls":      &p.WarmupCalls,
+		"warmup-ms":         &p.WarmupMs,
+	} {
+		if v, ok := metrics[unit]; ok {
+			*values = append(*values, v)
+		}
+	}
+}
+
+// ReadAll reads go test output as text or as a go test -json stream, or the
+// results written by the harness with -results. Errors name the line of the
+// input.
 func ReadAll(reader io.Reader) (Benchmark, error) {
-	bench := Benchmark{}
+	return ReadAllWhere(reader, nil)
+}
+
+// ReadAllWhere is ReadAll keeping only the results whose labels include every
+// label of where, e.g. pool=3-6 to pick one of several connection pools.
+func ReadAllWhere(reader io.Reader, where map[string]string) (Benchmark, error) {
+	bench := Benchmark{where: where}
 
-	scan := bufio.NewScanner(reader)
+	buffered := bufio.NewReader(reader)
 
-	for scan.Scan() {
-		line := scan.Text()
+	if first, err := peekNonSpace(buffered); err != nil || first != '{' {
+		return bench, bench.readText(buffered)
+	}
+
+	data, err := io.ReadAll(buffered)
+	if err != nil {
+		return bench, err
+	}
+
+	if isTestEvents(data) {
+		return bench, bench.readTestEvents(data)
+	}
+
+	results, err := ReadResults(bytes.NewReader(data))
+	if err != nil {
+		return bench, err
+	}
+
+	for _, r := range results.Benchmarks {
+		name, labels := parseName(r.Name)
+
+		if results.Environment.GOMAXPROCS > 0 {
+			labels["gomaxprocs"] = strconv.Itoa(results.Environment.GOMAXPROCS)
+		}
+
+		if err = bench.add(name, labels, r.Metrics); err != nil {
+			return bench, err
+		}
+	}
 
-		if !strings.HasPrefix(line, "Benchmark") {
+	return bench, nil
+}
+
+func (bench *Benchmark) readText(r io.Reader) error {
+	scan := bufio.NewScanner(r)
+
+	for n := 1; scan.Scan(); n++ {
+		if err := bench.addLine(scan.Text()); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+
+	return scan.Err()
+}
+
+// isTestEvents reports whether data is a go test -json stream, which has one
+// event with an Action per line.
+func isTestEvents(data []byte) bool {
+	first, _, _ := bytes.Cut(bytes.TrimSpace(data), []byte("\n"))
+
+	var event struct{ Action string }
+
+	return json.Unmarshal(first, &event) == nil && event.Action != ""
+}
+
+// readTestEvents joins the output events and reads it as text. A benchmark
+// line may be split over several events, e.g. its name and its result.
+func (bench *Benchmark) readTestEvents(data []byte) error {
+	var (
+		pending string
+		// last is the line of the event that ends pending
+		last int
+	)
+
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var event struct{ Action, Output string }
+
+		if err := json.Unmarshal(line, &event); err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if event.Action != "output" {
 			continue
 		}
 
-		b, err := parse.ParseLine(line)
+		lines := strings.Split(pending+event.Output, "\n")
+		pending = lines[len(lines)-1]
+		last = i + 1
+
+		for _, l := range lines[:len(lines)-1] {
+			if err := bench.addLine(l); err != nil {
+				return fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+	}
+
+	if err := bench.addLine(pending); err != nil {
+		return fmt.Errorf("line %d: %w", last, err)
+	}
+
+	return nil
+}
+
+// addLine adds a result line like
+// Benchmark/SQL/List/100-8  1000  1500 ns/op  100 B/op  12 warmup-calls.
+// Results of other benchmarks, e.g. BenchmarkMiddleware, are rejected. Other
+// lines, also a benchmark name followed by its log, are ignored.
+func (bench *Benchmark) addLine(line string) error {
+	if !strings.HasPrefix(line, "Benchmark") {
+		return nil
+	}
+
+	fields := strings.Fields(line)
+
+	if len(fields) < 2 {
+		return nil
+	}
+
+	if _, err := strconv.Atoi(fields[1]); err != nil {
+		return nil
+	}
+
+	if !strings.HasPrefix(fields[0], "Benchmark/") {
+		return fmt.Errorf("%s: not a result of Benchmark, select it with -bench='^Benchmark/'", fields[0])
+	}
+
+	if len(fields)%2 != 0 {
+		return fmt.Errorf("%s: missing unit of %s", fields[0], fields[len(fields)-1])
+	}
+
+	metrics := make(map[string]float64, len(fields)/2-1)
+
+	for i := 2; i < len(fields); i += 2 {
+		v, err := strconv.ParseFloat(fields[i], 64)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("%s: invalid value %q of %s", fields[0], fields[i], fields[i+1])
 		}
 
-		parts := strings.Split(b.Name, "/")
-
-		var framework *Framework
-
-		switch parts[1] {
-		case "SQL":
-			framework = &bench.SQL
-		case "PGX":
-			framework = &bench.PGX
-		case "SQUIRREL":
-			framework = &bench.SQUIRREL
-		case "SQLX":
-			framework = &bench.SQLX
-		case "GORM":
-			framework = &bench.GORM
-		case "SQLC":
-			framework = &bench.SQLC
-		case "SQLT":
-			framework = &bench.SQLT
-		case "SQLT-Cache":
-			framework = &bench.SQLTCACHE
-		default:
-			return bench, fmt.Errorf("invalid framework: %s", parts[1])
+		metrics[fields[i+1]] = v
+	}
+
+	name, labels := parseName(fields[0])
+
+	return bench.add(name, labels, metrics)
+}
+
+// parseName splits the labels off a benchmark name: key=value parts and the
+// -GOMAXPROCS suffix, which go test omits if it is 1.
+func parseName(full string) (string, map[string]string) {
+	labels := map[string]string{}
+
+	if i := strings.LastIndex(full, "-"); i > strings.LastIndex(full, "/") {
+		if _, err := strconv.Atoi(full[i+1:]); err == nil {
+			labels["gomaxprocs"] = full[i+1:]
+			full = full[:i]
+		}
+	}
+
+	var parts []string
+
+	for _, part := range strings.Split(full, "/") {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			labels[key] = value
+
+			continue
 		}
 
-		var szenario *Szenario
-
-		switch parts[2] {
-		case "List":
-			szenario = &framework.List
-		case "ListPreload":
-			szenario = &framework.ListPreload
-		case "Dashboard":
-			szenario = &framework.Dashboard
-		case "DashboardPreload":
-			szenario = &framework.DashboardPreload
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, "/"), labels
+}
+
+func (bench *Benchmark) add(name string, labels map[string]string, metrics map[string]float64) error {
+	parts := strings.Split(name, "/")
+	if len(parts) != 4 {
+		return fmt.Errorf("invalid benchmark: %s", name)
+	}
+
+	for key, value := range bench.where {
+		if labels[key] != value {
+			return nil
+		}
+	}
+
+	var framework *Framework
+
+	switch parts[1] {
+	case "SQL":
+		framework = &bench.SQL
+	case "PGX":
+		framework = &bench.PGX
+	case "SQUIRREL":
+		framework = &bench.SQUIRREL
+	case "SQLX":
+		framework = &bench.SQLX
+	case "GORM":
+		framework = &bench.GORM
+	case "SQLC":
+		framework = &bench.SQLC
+	case "SQLT":
+		framework = &bench.SQLT
+	case "SQLT-Cache":
+		framework = &bench.SQLTCACHE
+	default:
+		return fmt.Errorf("invalid framework: %s", parts[1])
+	}
+
+	var strategy *Strategy
+
+	switch parts[2] {
+	case "PreloadAny":
+		strategy = &framework.PreloadAny
+	case "PreloadUnnest":
+		strategy = &framework.PreloadUnnest
+	case "PreloadTempTable":
+		strategy = &framework.PreloadTempTable
+	case "PreloadBatch":
+		strategy = &framework.PreloadBatch
+	}
+
+	if strategy != nil {
+		var params *Params
+
+		switch parts[3] {
+		case "10":
+			params = &strategy.Ten
+		case "1000":
+			params = &strategy.Thousand
+		case "10000":
+			params = &strategy.TenThousand
+		case "50000":
+			params = &strategy.FiftyThousand
 		default:
-			return bench, fmt.Errorf("invalid szenario: %s", parts[2])
+			return fmt.Errorf("invalid params: %s", parts[3])
 		}
 
-		switch {
-		case strings.HasPrefix(parts[3], "100-"):
-			szenario.Hundred.NsPerOp = append(szenario.Hundred.NsPerOp, b.NsPerOp)
-			szenario.Hundred.BytesPerOp = append(szenario.Hundred.BytesPerOp, float64(b.AllocedBytesPerOp))
-			szenario.Hundred.AllocsPerOp = append(szenario.Hundred.AllocsPerOp, float64(b.AllocsPerOp))
-		case strings.HasPrefix(parts[3], "1000-"):
-			szenario.Thousand.NsPerOp = append(szenario.Thousand.NsPerOp, b.NsPerOp)
-			szenario.Thousand.BytesPerOp = append(szenario.Thousand.BytesPerOp, float64(b.AllocedBytesPerOp))
-			szenario.Thousand.AllocsPerOp = append(szenario.Thousand.AllocsPerOp, float64(b.AllocsPerOp))
+		params.append(labels, metrics)
+
+		return nil
+	}
+
+	var cache *Cache
+
+	switch parts[2] {
+	case "ListPreloadCached":
+		cache = &framework.ListPreloadCached
+	case "DashboardPreloadCached":
+		cache = &framework.DashboardPreloadCached
+	}
+
+	if cache != nil {
+		var params *Params
+
+		switch parts[3] {
+		case "10":
+			params = &cache.Ten
+		case "100":
+			params = &cache.Hundred
+		case "1000":
+			params = &cache.Thousand
 		default:
-			return bench, fmt.Errorf("invalid params: %s", parts[3])
+			return fmt.Errorf("invalid params: %s", parts[3])
 		}
+
+		params.append(labels, metrics)
+
+		return nil
 	}
 
-	return bench, nil
+	var szenario *Szenario
+
+	switch parts[2] {
+	case "List":
+		szenario = &framework.List
+	case "ListPreload":
+		szenario = &framework.ListPreload
+	case "ListPreloadLoader":
+		szenario = &framework.ListPreloadLoader
+	case "ListNPlusOne":
+		szenario = &framework.ListNPlusOne
+	case "ListJSON":
+		szenario = &framework.ListJSON
+	case "Dashboard":
+		szenario = &framework.Dashboard
+	case "DashboardPreload":
+		szenario = &framework.DashboardPreload
+	case "DashboardPreloadLoader":
+		szenario = &framework.DashboardPreloadLoader
+	case "Details":
+		szenario = &framework.Details
+	case "DetailsNull":
+		szenario = &framework.DetailsNull
+	case "Search":
+		szenario = &framework.Search
+	case "Websearch":
+		szenario = &framework.Websearch
+	case "Facets":
+		szenario = &framework.Facets
+	case "FacetsBatch":
+		szenario = &framework.FacetsBatch
+	case "TopRated":
+		szenario = &framework.TopRated
+	case "Collaborators":
+		szenario = &framework.Collaborators
+	case "Movie":
+		szenario = &framework.Movie
+	case "Wide":
+		szenario = &framework.Wide
+	default:
+		return fmt.Errorf("invalid szenario: %s", parts[2])
+	}
+
+	switch parts[3] {
+	case "100":
+		szenario.Hundred.append(labels, metrics)
+	case "1000":
+		szenario.Thousand.append(labels, metrics)
+	default:
+		return fmt.Errorf("invalid params: %s", parts[3])
+	}
+
+	return nil
 }
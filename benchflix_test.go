@@ -1,80 +1,143 @@
 package benchflix_test
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"io"
+	"log/slog"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
 	"runtime"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/go-sqlt/benchflix"
+	"github.com/go-sqlt/benchflix/cli"
 	"github.com/go-sqlt/benchflix/gormflix"
-	"github.com/go-sqlt/benchflix/pgxflix"
-	"github.com/go-sqlt/benchflix/sqlcflix"
-	"github.com/go-sqlt/benchflix/sqlflix"
-	"github.com/go-sqlt/benchflix/sqltflix"
-	"github.com/go-sqlt/benchflix/sqlxflix"
-	"github.com/go-sqlt/benchflix/squirrelflix"
-	"github.com/go-sqlt/sqlt"
+	_ "github.com/go-sqlt/benchflix/pgxflix"
+	_ "github.com/go-sqlt/benchflix/sqlcflix"
+	_ "github.com/go-sqlt/benchflix/sqlflix"
+	_ "github.com/go-sqlt/benchflix/sqltflix"
+	_ "github.com/go-sqlt/benchflix/sqlxflix"
+	_ "github.com/go-sqlt/benchflix/squirrelflix"
+	"github.com/google/pprof/profile"
+	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
-	"golang.org/x/sync/errgroup"
 )
 
 var (
-	MaxConns        = 6
-	MinConns        = 3
+	Phases     = flag.Bool("phases", false, "report build-ns/op, db-ns/op and scan-ns/op")
+	Statements = flag.String("statements", "", "write pg_stat_statements of every sub-benchmark to this file")
+	Profiles   = flag.Bool("profiles", false, "write cpu and allocation profiles of every sub-benchmark to data/profiles")
+	WarmupSpec = flag.String("warmup", "count:12500", "warmup policy: count:N, duration:D or steady:WINDOW:TOLERANCE:MAXCALLS")
+	Settle     = flag.Duration("settle", 500*time.Millisecond, "pause after the warmup")
+	Curves     = flag.String("warmup-curves", "", "write the warmup curve of every sub-benchmark to this file")
+	MatrixPath = flag.String("matrix", "", "run the frameworks, szenarios and sizes of this matrix file, explicit flags take precedence")
+	ResultsOut = flag.String("results", "", "write versioned json results with environment metadata to this file")
+)
+
+// Matrix is loaded from -matrix.
+var Matrix benchflix.Matrix
+
+// Warmup is parsed from -warmup, CurvesOut is set while -warmup-curves is given.
+var (
+	Warmup    benchflix.Warmup
+	CurvesOut *json.Encoder
+)
+
+// StatsDB and StatsOut are set while -statements is given.
+var (
+	StatsDB  *pgxpool.Pool
+	StatsOut *json.Encoder
+)
+
+// Pooled is the repository of the running framework, if it exposes its pool.
+var Pooled benchflix.Pooled
+
+// Latency is set while a LatencyBenchmark runs.
+var Latency bool
+
+// Cache is the repository of the running CachedBenchmark, its stats are reset
+// after the warmup.
+var Cache *benchflix.CachedRepository
+
+// Results collects every run while -results is given, Reported holds the
+// metrics of the running rounds until they are recorded.
+var (
+	Results  *benchflix.Results
+	Reported = map[*testing.B]map[string]float64{}
+)
+
+// Pool is the connection pool of the running sub-benchmark of Pools, tests
+// use the first.
+var (
+	Pools = []benchflix.MatrixPool{{Min: 3, Max: 6}}
+	Pool  = Pools[0]
+)
+
+var (
 	IdleTimeout     = 2 * time.Minute
 	ListParams      []benchflix.ListParams
 	DashboardParams []benchflix.DashboardParams
+	SearchParams    []benchflix.SearchParams
+	WebsearchParams []benchflix.SearchParams
+	TopParams       []benchflix.TopParams
+	GraphParams     []benchflix.GraphParams
+	MovieIDs        []int64
+	WideParams      []benchflix.WideParams
+	PreloadIDs      []int64
+	ParamSizes      = []int{100, 1000}
+	PreloadSizes    = []int{10, 1000, 10_000, 50_000}
+	LoaderWait      = 200 * time.Microsecond
+	LoaderMaxBatch  = 5000
+	CacheSizes      = []int{10, 100, 1000}
+	CacheTTL        = time.Minute
+	ListCached      []benchflix.ListParams
+	DashboardCached []benchflix.DashboardParams
 )
 
-type NamedRepository struct {
-	Name       string
-	Repository func(conn string, min, max int, idle time.Duration) benchflix.Repository
-}
-
-var repositories = []NamedRepository{
-	{
-		Name:       "SQL",
-		Repository: sqlflix.NewRepository,
-	},
-	{
-		Name:       "PGX",
-		Repository: pgxflix.NewRepository,
-	},
-	{
-		Name:       "SQUIRREL",
-		Repository: squirrelflix.NewRepository,
-	},
-	{
-		Name:       "SQLX",
-		Repository: sqlxflix.NewRepository,
-	},
-	{
-		Name:       "GORM",
-		Repository: gormflix.NewRepository,
-	},
-	{
-		Name:       "SQLC",
-		Repository: sqlcflix.NewRepository,
-	},
-	{
-		Name: "SQLT",
-		Repository: func(conn string, min, max int, idle time.Duration) benchflix.Repository {
-			return sqltflix.NewRepository(conn, min, max, idle, sqlt.Config{})
-		},
-	},
-	{
-		Name: "SQLT-Cache",
-		Repository: func(conn string, min, max int, idle time.Duration) benchflix.Repository {
-			return sqltflix.NewRepository(conn, min, max, idle, sqlt.ExpressionSize(10_000))
-		},
-	},
+// Frameworks are run in order, -matrix replaces them.
+var Frameworks = benchflix.DefaultFrameworks
+
+// OpenRepository opens the repository of the framework and closes it when the
+// test or benchmark is done.
+func OpenRepository(tb testing.TB, f benchflix.MatrixFramework, conn string, opts ...benchflix.Option) benchflix.Repository {
+	repo, err := f.Open(conn, Pool.Min, Pool.Max, IdleTimeout, opts...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	if closer, ok := repo.(io.Closer); ok {
+		tb.Cleanup(func() { _ = closer.Close() })
+	}
+
+	return repo
+}
+
+func ExecBenchmark[P, R any](exec func(context.Context, P) (R, error), params []P, b *testing.B) {
+	ExecBenchmarkWarmup(exec, params, Warmup, b)
 }
 
-func ExecBenchmark[P any](exec func(context.Context, P) ([]benchflix.Movie, error), params []P, b *testing.B) {
+func ExecBenchmarkWarmup[P, R any](exec func(context.Context, P) (R, error), params []P, warmup benchflix.Warmup, b *testing.B) {
 	_, err := exec(context.Background(), params[0])
 	if err == benchflix.ErrSkip {
 		b.SkipNow()
@@ -82,31 +145,67 @@ func ExecBenchmark[P any](exec func(context.Context, P) ([]benchflix.Movie, erro
 		return
 	}
 
-	var group errgroup.Group
+	size := len(params)
+
+	warmed, err := warmup.Run(Pool.Max, func(i int) error {
+		_, err := exec(context.Background(), params[i%size])
+
+		return err
+	})
+	if err != nil {
+		b.Fatal(err)
+
+		return
+	}
+
+	var build, db, scan, count atomic.Int64
 
-	group.SetLimit(MaxConns)
+	if *Phases {
+		query := exec
 
-	size := len(params)
+		exec = func(ctx context.Context, p P) (R, error) {
+			result, phases, err := benchflix.MeasurePhases(ctx, p, query)
 
-	for i := range 12_500 {
-		group.Go(func() error {
-			_, err := exec(context.Background(), params[i%size])
-			if err != nil {
-				return err
-			}
+			build.Add(int64(phases.Build))
+			db.Add(int64(phases.DB))
+			scan.Add(int64(phases.Scan))
+			count.Add(1)
 
-			return nil
-		})
+			return result, err
+		}
 	}
 
-	if err = group.Wait(); err != nil {
-		b.Fatal(err)
+	var statements []benchflix.StatementStats
 
-		return
+	if StatsDB != nil {
+		if statements, err = benchflix.SnapshotStatementStats(context.Background(), StatsDB); err != nil {
+			b.Fatal(err)
+		}
 	}
 
-	runtime.GC()
-	time.Sleep(500 * time.Millisecond)
+	var before benchflix.PoolStats
+
+	if Pooled != nil {
+		before = Pooled.PoolStats()
+	}
+
+	if Cache != nil {
+		Cache.ResetStats()
+	}
+
+	var stopProfiles func()
+
+	if *Profiles {
+		stopProfiles = ProfileBenchmark(b)
+	}
+
+	var memBefore, memAfter runtime.MemStats
+
+	if Results != nil {
+		b.Cleanup(func() { RecordResult(&memBefore, &memAfter, b) })
+
+		runtime.ReadMemStats(&memBefore)
+	}
 
 	b.ResetTimer()
 
@@ -124,76 +223,2186 @@ func ExecBenchmark[P any](exec func(context.Context, P) ([]benchflix.Movie, erro
 			i++
 		}
 	})
+
+	// every goroutine of RunParallel calls one after the other, so the mean
+	// latency follows from the time of the measured loop without wrapping exec
+	if Latency {
+		ReportMetric(b, float64(b.Elapsed())*float64(runtime.GOMAXPROCS(0))/float64(b.N), "latency-ns/op")
+	}
+
+	if Results != nil {
+		b.StopTimer()
+
+		runtime.ReadMemStats(&memAfter)
+
+		b.StartTimer()
+	}
+
+	if stopProfiles != nil {
+		b.StopTimer()
+
+		stopProfiles()
+	}
+
+	if Pooled != nil {
+		PoolBenchmark(Pooled.PoolStats().Sub(before), b)
+	}
+
+	if StatsDB != nil {
+		b.StopTimer()
+
+		StatementBenchmark(statements, b)
+	}
+
+	if n := count.Load(); n > 0 {
+		ReportMetric(b, float64(build.Load())/float64(n), "build-ns/op")
+		ReportMetric(b, float64(db.Load())/float64(n), "db-ns/op")
+		ReportMetric(b, float64(scan.Load())/float64(n), "scan-ns/op")
+	}
+
+	WarmupBenchmark(warmed, b)
 }
 
-func Benchmark(b *testing.B) {
-	params, err := os.Open("./params.json")
+// ReportMetric reports a metric of b and keeps it for -results.
+func ReportMetric(b *testing.B, n float64, unit string) {
+	b.ReportMetric(n, unit)
+
+	if Results == nil {
+		return
+	}
+
+	if Reported[b] == nil {
+		Reported[b] = map[string]float64{}
+	}
+
+	Reported[b][unit] = n
+}
+
+// RecordResult adds the round of b to Results. Every round with a larger b.N
+// replaces the previous one, b.N == 1 starts the next run of -count.
+func RecordResult(before, after *runtime.MemStats, b *testing.B) {
+	n := float64(b.N)
+
+	result := benchflix.Result{
+		Name: b.Name(),
+		N:    b.N,
+		Metrics: map[string]float64{
+			"ns/op":     float64(b.Elapsed().Nanoseconds()) / n,
+			"B/op":      float64(after.TotalAlloc-before.TotalAlloc) / n,
+			"allocs/op": float64(after.Mallocs-before.Mallocs) / n,
+		},
+	}
+
+	maps.Copy(result.Metrics, Reported[b])
+	delete(Reported, b)
+
+	last := len(Results.Benchmarks) - 1
+
+	if b.N > 1 && last >= 0 && Results.Benchmarks[last].Name == result.Name {
+		Results.Benchmarks[last] = result
+
+		return
+	}
+
+	Results.Benchmarks = append(Results.Benchmarks, result)
+}
+
+// WarmupBenchmark reports the length of the warmup and writes its curve to
+// CurvesOut. Every round of b.N writes a line, the last one is the measured run.
+func WarmupBenchmark(warmed benchflix.WarmupResult, b *testing.B) {
+	ReportMetric(b, float64(warmed.Calls), "warmup-calls")
+	ReportMetric(b, float64(warmed.Duration.Milliseconds()), "warmup-ms")
+
+	if CurvesOut == nil {
+		return
+	}
+
+	curve := make([]int64, len(warmed.Curve))
+
+	for i, d := range warmed.Curve {
+		curve[i] = d.Nanoseconds()
+	}
+
+	if err := CurvesOut.Encode(struct {
+		Benchmark string  `json:"benchmark"`
+		N         int     `json:"n"`
+		Calls     int     `json:"calls"`
+		Duration  int64   `json:"duration_ns"`
+		Window    int     `json:"window"`
+		Steady    bool    `json:"steady"`
+		Curve     []int64 `json:"curve_ns"`
+	}{
+		Benchmark: b.Name(),
+		N:         b.N,
+		Calls:     warmed.Calls,
+		Duration:  warmed.Duration.Nanoseconds(),
+		Window:    Warmup.Window,
+		Steady:    warmed.Steady,
+		Curve:     curve,
+	}); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// ProfileBenchmark starts a cpu profile and remembers the allocations so far.
+// The returned function stops the profile and writes it, together with the
+// allocations made in between, to data/profiles. Every round of b.N overwrites
+// the files, the last one is the measured run.
+func ProfileBenchmark(b *testing.B) func() {
+	name := filepath.Join("data", "profiles", strings.ReplaceAll(strings.TrimPrefix(b.Name(), "Benchmark/"), "/", "_"))
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		b.Fatal(err)
+	}
+
+	cpu, err := os.Create(name + ".cpu.pprof")
 	if err != nil {
-		panic(err)
+		b.Fatal(err)
+	}
+
+	var before bytes.Buffer
+
+	// like after the run, so that the warmup is not attributed to it
+	runtime.GC()
+
+	if err = pprof.Lookup("allocs").WriteTo(&before, 0); err != nil {
+		b.Fatal(err)
+	}
+
+	if err = pprof.StartCPUProfile(cpu); err != nil {
+		b.Fatal(err)
+	}
+
+	return func() {
+		pprof.StopCPUProfile()
+
+		if err := cpu.Close(); err != nil {
+			b.Fatal(err)
+		}
+
+		// the allocs profile is only updated by a garbage collection
+		runtime.GC()
+
+		var after bytes.Buffer
+
+		if err := pprof.Lookup("allocs").WriteTo(&after, 0); err != nil {
+			b.Fatal(err)
+		}
+
+		start, err := profile.Parse(&before)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		end, err := profile.Parse(&after)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		delta, err := benchflix.DiffProfiles(start, end)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		allocs, err := os.Create(name + ".allocs.pprof")
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		defer allocs.Close()
+
+		if err = delta.Write(allocs); err != nil {
+			b.Fatal(err)
+		}
 	}
+}
+
+// PoolBenchmark reports the pool counters accumulated during the run. The
+// acquire counters are those of pgxpool, the wait counters those of
+// database/sql.
+func PoolBenchmark(stats benchflix.PoolStats, b *testing.B) {
+	n := float64(b.N)
+
+	if stats.HasAcquires {
+		ReportMetric(b, float64(stats.Acquires)/n, "acquires/op")
+		ReportMetric(b, float64(stats.EmptyAcquires)/n, "empty-acquires/op")
+		ReportMetric(b, stats.NsPerAcquire(), "ns/acquire")
+	}
+
+	if stats.HasWaits {
+		ReportMetric(b, float64(stats.Waits)/n, "waits/op")
+		ReportMetric(b, float64(stats.WaitDuration)/n, "wait-ns/op")
+	}
+
+	ReportMetric(b, float64(stats.NewConns), "new-conns")
+	ReportMetric(b, float64(stats.ClosedConns), "closed-conns")
+}
 
-	data, err := io.ReadAll(params)
+// StatementBenchmark reports the server side cost per op from pg_stat_statements
+// since the snapshot before and writes the statements to StatsOut. Every round
+// of b.N writes a line, the last one of a benchmark is the measured run.
+func StatementBenchmark(before []benchflix.StatementStats, b *testing.B) {
+	after, err := benchflix.SnapshotStatementStats(context.Background(), StatsDB)
 	if err != nil {
-		panic(err)
+		b.Fatal(err)
 	}
 
-	if err = json.Unmarshal(data, &DashboardParams); err != nil {
-		panic(err)
+	stats := benchflix.DiffStatementStats(before, after)
+	sum := benchflix.SumStatementStats(stats)
+	n := float64(b.N)
+
+	ReportMetric(b, float64(sum.Calls)/n, "stmts/op")
+	ReportMetric(b, float64(sum.Rows)/n, "rows/op")
+	ReportMetric(b, float64(sum.SharedBlksHit)/n, "hits/op")
+	ReportMetric(b, sum.TotalExecTime*1e6/n, "server-ns/op")
+	ReportMetric(b, sum.TotalPlanTime*1e6/n, "plan-ns/op")
+
+	if err = StatsOut.Encode(struct {
+		Benchmark  string                     `json:"benchmark"`
+		N          int                        `json:"n"`
+		Statements []benchflix.StatementStats `json:"statements"`
+	}{
+		Benchmark:  b.Name(),
+		N:          b.N,
+		Statements: stats,
+	}); err != nil {
+		b.Fatal(err)
 	}
+}
+
+// LatencyBenchmark wraps ExecBenchmark and additionally reports the mean
+// latency of a single measured call, which batching trades for throughput.
+func LatencyBenchmark[P, R any](exec func(context.Context, P) (R, error), params []P, b *testing.B) {
+	Latency = true
+
+	defer func() { Latency = false }()
+
+	ExecBenchmark(exec, params, b)
+}
+
+// CachedBenchmark runs one sub-benchmark per cache size and reports the hit
+// ratio that size achieved on the skewed params in the measured loop. Every
+// cache listens for writes to the database at conn.
+func CachedBenchmark[P, R any](conn string, repo benchflix.Repository, query func(*benchflix.CachedRepository, context.Context, P) (R, error), params []P, b *testing.B) {
+	for _, size := range CacheSizes {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			cache := benchflix.NewCachedRepository(repo, CacheTTL, size)
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			defer cancel()
+
+			if err := cache.Listen(ctx, conn); err != nil {
+				b.Fatal(err)
+			}
+
+			Cache = cache
 
-	if err = json.Unmarshal(data, &ListParams); err != nil {
-		panic(err)
+			defer func() { Cache = nil }()
+
+			ExecBenchmark(func(ctx context.Context, p P) (R, error) {
+				return query(cache, ctx, p)
+			}, params, b)
+
+			ReportMetric(b, cache.HitRatio(), "hit-ratio")
+		})
 	}
+}
 
-	for _, r := range repositories {
-		b.Run(r.Name, func(b *testing.B) {
-			conn, resource := benchflix.InitializePostgres(r.Name)
+// Szenario runs fn as a sub-benchmark unless the matrix leaves the szenario out.
+func Szenario(b *testing.B, name string, fn func(b *testing.B)) {
+	if !slices.Contains(benchflix.Szenarios, name) {
+		b.Fatalf("szenario %s is missing in benchflix.Szenarios", name)
+	}
 
-			defer resource.Close()
+	if !Matrix.HasSzenario(name) {
+		return
+	}
 
-			repo := r.Repository(conn, MinConns, MaxConns, IdleTimeout)
+	b.Run(name, fn)
+}
 
-			b.Run("List", func(b *testing.B) {
-				b.Run("100", func(b *testing.B) {
-					ExecBenchmark(repo.QueryList, ListParams[:100], b)
-				})
+// Sizes runs bench as a sub-benchmark for every size of ParamSizes with the
+// first size params.
+func Sizes[P, R any](b *testing.B, bench func(func(context.Context, P) (R, error), []P, *testing.B), exec func(context.Context, P) (R, error), params []P) {
+	for _, size := range ParamSizes {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			if size > len(params) {
+				b.Fatalf("size %d exceeds the %d params", size, len(params))
+			}
 
-				b.Run("1000", func(b *testing.B) {
-					ExecBenchmark(repo.QueryList, ListParams[:1000], b)
-				})
-			})
+			bench(exec, params[:size], b)
+		})
+	}
+}
 
-			b.Run("ListPreload", func(b *testing.B) {
-				b.Run("100", func(b *testing.B) {
-					ExecBenchmark(repo.QueryListPreload, ListParams[:100], b)
-				})
+// PreloadBenchmark runs one sub-benchmark per preload size. Larger id sets get
+// fewer warmup iterations so that every size does roughly the same work.
+func PreloadBenchmark(exec func(context.Context, benchflix.PreloadParams) ([]benchflix.MovieDirectors, error), strategy benchflix.PreloadStrategy, b *testing.B) {
+	for _, size := range PreloadSizes {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			params := make([]benchflix.PreloadParams, 10)
 
-				b.Run("1000", func(b *testing.B) {
-					ExecBenchmark(repo.QueryListPreload, ListParams[:1000], b)
-				})
-			})
+			for i := range params {
+				ids := make([]int64, size)
 
-			b.Run("Dashboard", func(b *testing.B) {
-				b.Run("100", func(b *testing.B) {
-					ExecBenchmark(repo.QueryDashboard, DashboardParams[:100], b)
-				})
+				for j := range ids {
+					ids[j] = PreloadIDs[(i*size+j)%len(PreloadIDs)]
+				}
 
-				b.Run("1000", func(b *testing.B) {
-					ExecBenchmark(repo.QueryDashboard, DashboardParams[:1000], b)
-				})
-			})
+				params[i] = benchflix.PreloadParams{
+					Strategy: strategy,
+					IDs:      ids,
+				}
+			}
 
-			b.Run("DashboardPreload", func(b *testing.B) {
-				b.Run("100", func(b *testing.B) {
-					ExecBenchmark(repo.QueryDashboardPreload, DashboardParams[:100], b)
-				})
+			warmup := Warmup
 
-				b.Run("1000", func(b *testing.B) {
-					ExecBenchmark(repo.QueryDashboardPreload, DashboardParams[:1000], b)
-				})
-			})
+			if warmup.Mode == benchflix.WarmupCount {
+				warmup.Count = max(Pool.Max, warmup.Count*10/size)
+			}
 
-			_ = resource.Close()
+			ExecBenchmarkWarmup(exec, params, warmup, b)
 		})
 	}
 }
+
+func LoadParams() {
+	params := benchflix.Must(benchflix.LoadParamSet("./params.json"))
+
+	ListParams = params.List
+	DashboardParams = params.Dashboard
+	SearchParams = params.Search
+	WebsearchParams = params.Websearch
+	TopParams = params.Top
+	GraphParams = params.Graph
+	MovieIDs = params.MovieIDs
+	WideParams = params.Wide
+
+	// Real traffic repeats a few popular queries far more often than the rest.
+	zipf := rand.NewZipf(rand.New(rand.NewPCG(1, 2)), 1.1, 1, 999)
+
+	ListCached = make([]benchflix.ListParams, 10_000)
+	DashboardCached = make([]benchflix.DashboardParams, 10_000)
+
+	for i := range ListCached {
+		n := zipf.Uint64()
+
+		ListCached[i] = ListParams[n%uint64(len(ListParams))]
+		DashboardCached[i] = DashboardParams[n%uint64(len(DashboardParams))]
+	}
+
+	PreloadIDs = make([]int64, 0, slices.Max(PreloadSizes))
+
+	var maxID int64
+
+	for _, m := range benchflix.Movies {
+		if len(PreloadIDs) == cap(PreloadIDs) {
+			break
+		}
+
+		PreloadIDs = append(PreloadIDs, m.ID)
+		maxID = max(maxID, m.ID)
+	}
+
+	for len(PreloadIDs) < cap(PreloadIDs) {
+		maxID++
+		PreloadIDs = append(PreloadIDs, maxID)
+	}
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	if *MatrixPath != "" {
+		ApplyMatrix(benchflix.Must(benchflix.LoadMatrix(*MatrixPath)))
+	}
+
+	os.Exit(m.Run())
+}
+
+// ApplyMatrix replaces the defaults of the harness with those of the matrix.
+// Flags given on the command line keep their value.
+func ApplyMatrix(m benchflix.Matrix) {
+	Matrix = m
+
+	explicit := map[string]bool{}
+
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	set := func(name, value string) {
+		if !explicit[name] {
+			if err := flag.Set(name, value); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	if m.Count > 0 {
+		set("test.count", strconv.Itoa(m.Count))
+	}
+
+	if m.Timeout > 0 {
+		set("test.timeout", time.Duration(m.Timeout).String())
+	}
+
+	if m.Warmup != "" {
+		set("warmup", m.Warmup)
+	}
+
+	if m.Settle > 0 {
+		set("settle", time.Duration(m.Settle).String())
+	}
+
+	if len(m.Frameworks) > 0 {
+		Frameworks = m.Frameworks
+	}
+
+	if len(m.Sizes) > 0 {
+		ParamSizes = m.Sizes
+	}
+
+	if len(m.PreloadSizes) > 0 {
+		PreloadSizes = m.PreloadSizes
+	}
+
+	if len(m.CacheSizes) > 0 {
+		CacheSizes = m.CacheSizes
+	}
+
+	if len(m.Pools) > 0 {
+		Pools = m.Pools
+		Pool = Pools[0]
+	}
+
+	if m.IdleTimeout > 0 {
+		IdleTimeout = time.Duration(m.IdleTimeout)
+	}
+}
+
+// WriteResults writes all runs recorded so far to -results.
+func WriteResults(b *testing.B) {
+	data, err := json.MarshalIndent(Results, "", "  ")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if err = os.WriteFile(*ResultsOut, append(data, '\n'), 0o644); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark(b *testing.B) {
+	LoadParams()
+
+	Warmup = benchflix.Must(benchflix.ParseWarmup(*WarmupSpec))
+	Warmup.Settle = *Settle
+
+	// -count calls Benchmark repeatedly, the outputs are created by the first
+	// call and stay open until the process exits.
+	if *Curves != "" && CurvesOut == nil {
+		CurvesOut = json.NewEncoder(benchflix.Must(os.Create(*Curves)))
+	}
+
+	if *Statements != "" && StatsOut == nil {
+		StatsOut = json.NewEncoder(benchflix.Must(os.Create(*Statements)))
+	}
+
+	if *ResultsOut != "" {
+		if Results == nil {
+			Results = &benchflix.Results{Version: benchflix.ResultsVersion, Environment: benchflix.NewEnvironment()}
+		}
+
+		defer WriteResults(b)
+	}
+
+	for _, f := range Frameworks {
+		b.Run(f.Name, func(b *testing.B) {
+			conn, resource := benchflix.InitializePostgres(f.Name)
+
+			defer resource.Close()
+
+			if Results != nil && Results.Environment.Postgres == "" {
+				if err := Results.Environment.DescribePostgres(context.Background(), conn, resource); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			if StatsOut != nil {
+				StatsDB = benchflix.Must(pgxpool.New(context.Background(), conn))
+
+				defer func() {
+					StatsDB.Close()
+					StatsDB = nil
+				}()
+			}
+
+			for _, pool := range Pools {
+				b.Run("pool="+pool.String(), func(b *testing.B) {
+					Pool = pool
+
+					defer func() { Pool = Pools[0] }()
+
+					var opts []benchflix.Option
+
+					// the instrumentation adds overhead, only pay it when measuring phases
+					if *Phases {
+						opts = append(opts, benchflix.WithInstrumentation())
+					}
+
+					repo := OpenRepository(b, f, conn, opts...)
+
+					Pooled, _ = repo.(benchflix.Pooled)
+
+					defer func() { Pooled = nil }()
+
+					Szenario(b, "List", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryList, ListParams)
+					})
+
+					Szenario(b, "ListPreload", func(b *testing.B) {
+						Sizes(b, LatencyBenchmark, repo.QueryListPreload, ListParams)
+					})
+
+					Szenario(b, "ListPreloadCached", func(b *testing.B) {
+						CachedBenchmark(conn, repo, (*benchflix.CachedRepository).QueryListPreload, ListCached, b)
+					})
+
+					Szenario(b, "ListPreloadLoader", func(b *testing.B) {
+						loadable, ok := repo.(benchflix.Loadable)
+						if !ok {
+							b.SkipNow()
+						}
+
+						repo := loadable.WithLoader(LoaderWait, LoaderMaxBatch)
+
+						Sizes(b, LatencyBenchmark, repo.QueryListPreload, ListParams)
+					})
+
+					Szenario(b, "ListNPlusOne", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryListNPlusOne, ListParams)
+					})
+
+					Szenario(b, "ListJSON", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryListJSON, ListParams)
+					})
+
+					Szenario(b, "Dashboard", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryDashboard, DashboardParams)
+					})
+
+					Szenario(b, "DashboardPreload", func(b *testing.B) {
+						Sizes(b, LatencyBenchmark, repo.QueryDashboardPreload, DashboardParams)
+					})
+
+					Szenario(b, "DashboardPreloadCached", func(b *testing.B) {
+						CachedBenchmark(conn, repo, (*benchflix.CachedRepository).QueryDashboardPreload, DashboardCached, b)
+					})
+
+					Szenario(b, "DashboardPreloadLoader", func(b *testing.B) {
+						loadable, ok := repo.(benchflix.Loadable)
+						if !ok {
+							b.SkipNow()
+						}
+
+						repo := loadable.WithLoader(LoaderWait, LoaderMaxBatch)
+
+						Sizes(b, LatencyBenchmark, repo.QueryDashboardPreload, DashboardParams)
+					})
+
+					Szenario(b, "Details", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryDetails, ListParams)
+					})
+
+					Szenario(b, "DetailsNull", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryDetailsNull, ListParams)
+					})
+
+					Szenario(b, "Search", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QuerySearch, SearchParams)
+					})
+
+					Szenario(b, "Websearch", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QuerySearch, WebsearchParams)
+					})
+
+					Szenario(b, "Facets", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryFacets, DashboardParams)
+					})
+
+					Szenario(b, "FacetsBatch", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryFacetsBatch, DashboardParams)
+					})
+
+					Szenario(b, "TopRated", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryTopRated, TopParams)
+					})
+
+					Szenario(b, "Collaborators", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryCollaborators, GraphParams)
+					})
+
+					Szenario(b, "Movie", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryMovie, MovieIDs)
+					})
+
+					Szenario(b, "Wide", func(b *testing.B) {
+						Sizes(b, ExecBenchmark, repo.QueryWide, WideParams)
+					})
+
+					Szenario(b, "PreloadAny", func(b *testing.B) {
+						PreloadBenchmark(repo.QueryDirectors, benchflix.PreloadAny, b)
+					})
+
+					Szenario(b, "PreloadUnnest", func(b *testing.B) {
+						PreloadBenchmark(repo.QueryDirectors, benchflix.PreloadUnnest, b)
+					})
+
+					Szenario(b, "PreloadTempTable", func(b *testing.B) {
+						PreloadBenchmark(repo.QueryDirectors, benchflix.PreloadTempTable, b)
+					})
+
+					Szenario(b, "PreloadBatch", func(b *testing.B) {
+						PreloadBenchmark(repo.QueryDirectors, benchflix.PreloadBatch, b)
+					})
+
+					_ = resource.Close()
+				})
+			}
+		})
+	}
+}
+
+func TestLoader(t *testing.T) {
+	var calls atomic.Int64
+
+	loader := benchflix.NewLoader(20*time.Millisecond, 8, func(_ context.Context, keys []int64) (map[int64]int64, error) {
+		calls.Add(1)
+
+		values := make(map[int64]int64, len(keys))
+
+		for _, k := range keys {
+			if k%2 == 0 {
+				values[k] = k * 10
+			}
+		}
+
+		return values, nil
+	})
+
+	var group sync.WaitGroup
+
+	for i := range 4 {
+		group.Add(1)
+
+		go func() {
+			defer group.Done()
+
+			keys := []int64{int64(i), int64(i + 1)}
+
+			values, err := loader.LoadMany(context.Background(), keys)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			for _, k := range keys {
+				v, ok := values[k]
+
+				if k%2 == 0 && (!ok || v != k*10) {
+					t.Errorf("key %d: got %d, want %d", k, v, k*10)
+				}
+
+				if k%2 != 0 && ok {
+					t.Errorf("key %d: unexpected value %d", k, v)
+				}
+			}
+		}()
+	}
+
+	group.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fetch calls: got %d, want 1", n)
+	}
+
+	keys := make([]int64, 20)
+
+	for i := range keys {
+		keys[i] = int64(i)
+	}
+
+	calls.Store(0)
+
+	if _, err := loader.LoadMany(context.Background(), keys); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := calls.Load(); n != 3 {
+		t.Errorf("fetch calls with max batch: got %d, want 3", n)
+	}
+}
+
+func TestCachedRepository(t *testing.T) {
+	var (
+		repo  = &countingRepository{}
+		cache = benchflix.NewCachedRepository(repo, time.Minute, 2)
+		ctx   = context.Background()
+		a     = benchflix.ListParams{Search: "a"}
+		b     = benchflix.ListParams{Search: "b"}
+		c     = benchflix.ListParams{Search: "c"}
+	)
+
+	for _, params := range []benchflix.ListParams{a, a, b, a, c, b} {
+		movies, err := cache.QueryList(ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(movies) != 1 || movies[0].Title != params.Search {
+			t.Errorf("params %q: got %v", params.Search, movies)
+		}
+	}
+
+	// a, b and a are served from the database, c evicts b as least recently used.
+	if n := repo.calls.Load(); n != 4 {
+		t.Errorf("queries: got %d, want 4", n)
+	}
+
+	if ratio := cache.HitRatio(); ratio != 2.0/6.0 {
+		t.Errorf("hit ratio: got %f, want %f", ratio, 2.0/6.0)
+	}
+
+	cache.ResetStats()
+
+	if ratio := cache.HitRatio(); ratio != 0 {
+		t.Errorf("hit ratio after reset: got %f, want 0", ratio)
+	}
+
+	cache.Invalidate()
+
+	if _, err := cache.QueryList(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := repo.calls.Load(); n != 5 {
+		t.Errorf("queries after invalidate: got %d, want 5", n)
+	}
+}
+
+// blockingRepository answers QueryList once release is closed and records the
+// error of the context the query ran with.
+type blockingRepository struct {
+	benchflix.Repository
+
+	started, release chan struct{}
+	err              error
+}
+
+func (r *blockingRepository) QueryList(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	close(r.started)
+	<-r.release
+
+	r.err = ctx.Err()
+
+	return []benchflix.Movie{{Title: params.Search}}, nil
+}
+
+func TestCachedRepositoryCalls(t *testing.T) {
+	var (
+		repo   = &blockingRepository{started: make(chan struct{}), release: make(chan struct{})}
+		cache  = benchflix.NewCachedRepository(repo, time.Minute, 2)
+		params = benchflix.ListParams{Search: "a"}
+		first  = make(chan error)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		_, err := cache.QueryList(ctx, params)
+		first <- err
+	}()
+
+	<-repo.started
+
+	// a waiter giving up returns at once, the call goes on for the others
+	canceled, cancelWaiter := context.WithCancel(context.Background())
+	cancelWaiter()
+
+	if _, err := cache.QueryList(canceled, params); !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled waiter: got %v", err)
+	}
+
+	second := make(chan []benchflix.Movie)
+
+	go func() {
+		movies, _ := cache.QueryList(context.Background(), params)
+		second <- movies
+	}()
+
+	cancel()
+	close(repo.release)
+
+	if err := <-first; err != nil {
+		t.Fatalf("first caller: %v", err)
+	}
+
+	if movies := <-second; len(movies) != 1 || movies[0].Title != "a" {
+		t.Fatalf("second caller: got %v", movies)
+	}
+
+	if repo.err != nil {
+		t.Fatalf("query ran with the cancellation of the first caller: %v", repo.err)
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("requires docker")
+	}
+
+	conn, resource := benchflix.InitializePostgres("Cache")
+
+	defer resource.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	cache := benchflix.NewCachedRepository(OpenRepository(t, Frameworks[0], conn), time.Hour, 10)
+
+	if err := cache.Listen(ctx, conn); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		movie  = benchflix.Movies[0]
+		params = benchflix.ListParams{Search: movie.Title, Limit: 1000}
+		title  = movie.Title + " Redux"
+	)
+
+	titleOf := func() string {
+		movies, err := cache.QueryList(ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, m := range movies {
+			if m.ID == movie.ID {
+				return m.Title
+			}
+		}
+
+		return ""
+	}
+
+	if got := titleOf(); got != movie.Title {
+		t.Fatalf("title before update: got %q", got)
+	}
+
+	db := benchflix.Must(pgxpool.New(ctx, conn))
+
+	defer db.Close()
+
+	if _, err := db.Exec(ctx, `UPDATE movies SET title = $1 WHERE id = $2`, title, movie.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); titleOf() != title; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("cache not invalidated by the update")
+		}
+	}
+}
+
+type countingRepository struct {
+	benchflix.Repository
+
+	calls atomic.Int64
+}
+
+func (r *countingRepository) QueryList(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	r.calls.Add(1)
+
+	return []benchflix.Movie{{Title: params.Search}}, nil
+}
+
+func (r *countingRepository) QueryMovie(context.Context, int64) (benchflix.Movie, error) {
+	r.calls.Add(1)
+
+	return benchflix.Movie{}, benchflix.ErrSkip
+}
+
+// closingRepository is Pooled, Loadable and an io.Closer like the adapters.
+type closingRepository struct {
+	*countingRepository
+
+	closed bool
+}
+
+func (r *closingRepository) PoolStats() benchflix.PoolStats {
+	return benchflix.PoolStats{NewConns: 1}
+}
+
+func (r *closingRepository) WithLoader(time.Duration, int) benchflix.Repository {
+	return r
+}
+
+func (r *closingRepository) Close() error {
+	r.closed = true
+
+	return nil
+}
+
+// BenchmarkMiddleware measures the overhead of the instrumentation itself
+// against a repository that does not touch the database.
+func BenchmarkMiddleware(b *testing.B) {
+	var (
+		ctx    = context.Background()
+		params = benchflix.ListParams{Search: "a", Limit: 10}
+		logger = slog.New(slog.NewJSONHandler(io.Discard, nil))
+	)
+
+	middlewares := []struct {
+		Name        string
+		Middlewares func() []benchflix.Middleware
+	}{
+		{
+			Name:        "None",
+			Middlewares: func() []benchflix.Middleware { return nil },
+		},
+		{
+			Name:        "Metrics",
+			Middlewares: func() []benchflix.Middleware { return []benchflix.Middleware{benchflix.NewMetrics().Middleware} },
+		},
+		{
+			Name:        "Logging",
+			Middlewares: func() []benchflix.Middleware { return []benchflix.Middleware{benchflix.Logging(logger)} },
+		},
+		{
+			Name: "Tracing",
+			Middlewares: func() []benchflix.Middleware {
+				return []benchflix.Middleware{benchflix.NewTracer(io.Discard, "benchflix").Middleware}
+			},
+		},
+		{
+			Name: "All",
+			Middlewares: func() []benchflix.Middleware {
+				return []benchflix.Middleware{
+					benchflix.NewTracer(io.Discard, "benchflix").Middleware,
+					benchflix.NewMetrics().Middleware,
+					benchflix.Logging(logger),
+				}
+			},
+		},
+	}
+
+	for _, m := range middlewares {
+		b.Run(m.Name, func(b *testing.B) {
+			repo := benchflix.Chain(&countingRepository{}, m.Middlewares()...)
+
+			b.ReportAllocs()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := repo.QueryList(ctx, params); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		metrics = benchflix.NewMetrics()
+		spans   bytes.Buffer
+		logs    bytes.Buffer
+		repo    = benchflix.Chain(&countingRepository{},
+			benchflix.NewTracer(&spans, "benchflix").Middleware,
+			metrics.Middleware,
+			benchflix.Logging(slog.New(slog.NewJSONHandler(&logs, nil))),
+		)
+	)
+
+	for _, search := range []string{"a", "b"} {
+		movies, err := repo.QueryList(ctx, benchflix.ListParams{Search: search})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(movies) != 1 || movies[0].Title != search {
+			t.Errorf("search %q: got %v", search, movies)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	for _, want := range []string{
+		`benchflix_repository_calls_total{method="QueryList",status="ok"} 2`,
+		`benchflix_repository_duration_seconds_bucket{method="QueryList",le="+Inf"} 2`,
+		`benchflix_repository_duration_seconds_count{method="QueryList"} 2`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics: missing %s in\n%s", want, rec.Body.String())
+		}
+	}
+
+	var record struct {
+		Method  string `json:"method"`
+		Results int    `json:"results"`
+	}
+
+	if err := json.Unmarshal(bytes.SplitN(logs.Bytes(), []byte("\n"), 2)[0], &record); err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Method != "QueryList" || record.Results != 1 {
+		t.Errorf("log: got %+v", record)
+	}
+
+	var (
+		dec     = json.NewDecoder(&spans)
+		traceID = map[string]bool{}
+	)
+
+	for dec.More() {
+		var request struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []struct {
+						TraceID string `json:"traceId"`
+						SpanID  string `json:"spanId"`
+						Name    string `json:"name"`
+					} `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+
+		if err := dec.Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+
+		span := request.ResourceSpans[0].ScopeSpans[0].Spans[0]
+
+		if span.Name != "QueryList" || len(span.TraceID) != 32 || len(span.SpanID) != 16 {
+			t.Errorf("span: got %+v", span)
+		}
+
+		traceID[span.TraceID] = true
+	}
+
+	if len(traceID) != 2 {
+		t.Errorf("traces: got %d, want 2", len(traceID))
+	}
+
+	// ErrSkip is no failure for any of the middlewares
+	spans.Reset()
+	logs.Reset()
+
+	if _, err := repo.QueryMovie(ctx, 1); !errors.Is(err, benchflix.ErrSkip) {
+		t.Fatalf("skip: got %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if want := `benchflix_repository_calls_total{method="QueryMovie",status="skipped"} 1`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("metrics: missing %s in\n%s", want, rec.Body.String())
+	}
+
+	if !strings.Contains(logs.String(), `"level":"INFO"`) || !strings.Contains(logs.String(), `"status":"skipped"`) {
+		t.Errorf("log: got %s", logs.String())
+	}
+
+	if strings.Contains(spans.String(), `"code":2`) || !strings.Contains(spans.String(), `"stringValue":"skipped"`) {
+		t.Errorf("span: got %s", spans.String())
+	}
+
+	// the adapter interfaces survive the chain, but are not made up
+	if _, ok := repo.(benchflix.Pooled); ok {
+		t.Error("counting repository: want not pooled")
+	}
+
+	inner := &closingRepository{countingRepository: &countingRepository{}}
+	chained := benchflix.Chain(inner, metrics.Middleware, benchflix.Logging(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+
+	if pooled, ok := chained.(benchflix.Pooled); !ok || pooled.PoolStats().NewConns != 1 {
+		t.Error("chain: want pooled")
+	}
+
+	loadable, ok := chained.(benchflix.Loadable)
+	if !ok {
+		t.Fatal("chain: want loadable")
+	}
+
+	if _, err := loadable.WithLoader(time.Millisecond, 10).QueryList(ctx, benchflix.ListParams{Search: "c"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec = httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if want := `benchflix_repository_calls_total{method="QueryList",status="ok"} 3`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("loader: missing %s in\n%s", want, rec.Body.String())
+	}
+
+	closer, ok := chained.(io.Closer)
+	if !ok {
+		t.Fatal("chain: want closer")
+	}
+
+	if err := closer.Close(); err != nil || !inner.closed {
+		t.Errorf("close: got %v, closed %v", err, inner.closed)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	for _, c := range []struct{ SQL, Want string }{
+		{"-- name: ListMovies :many\nSELECT id FROM movies", "SELECT"},
+		{"SELECT\n\tid", "SELECT"},
+		{" /* comment */ with a AS (SELECT 1) SELECT * FROM a", "WITH"},
+		{"/* one */ -- two\n/* three */ insert INTO movies", "INSERT"},
+		{"-- only a comment", ""},
+		{"/* unterminated", ""},
+		{"", ""},
+	} {
+		if got := benchflix.Keyword(c.SQL); got != c.Want {
+			t.Errorf("keyword %q: got %q, want %q", c.SQL, got, c.Want)
+		}
+	}
+
+	for _, c := range []struct {
+		Name  string
+		Plans []benchflix.Plan
+		Want  bool
+	}{
+		{"single", []benchflix.Plan{{Shape: "Seq Scan", Cost: 10}}, false},
+		{"within tolerance", []benchflix.Plan{{Shape: "Seq Scan", Cost: 10}, {Shape: "Seq Scan", Cost: 10.5}}, false},
+		{"shape", []benchflix.Plan{{Shape: "Seq Scan", Cost: 10}, {Shape: "Index Scan", Cost: 10}}, true},
+		{"cost", []benchflix.Plan{{Shape: "Seq Scan", Cost: 10}, {Shape: "Seq Scan", Cost: 12}}, true},
+	} {
+		if got := benchflix.Divergent(c.Plans, 0.1); got != c.Want {
+			t.Errorf("divergent %s: got %t, want %t", c.Name, got, c.Want)
+		}
+	}
+
+	// countingRepository sends no sql at all, like an adapter dropping ctx
+	if _, err := benchflix.CapturePlanned(context.Background(), &countingRepository{}, benchflix.ListParams{}, benchflix.Repository.QueryList); err == nil {
+		t.Error("capture: want error without statements")
+	}
+}
+
+func TestPhases(t *testing.T) {
+	for _, instrument := range []bool{true, false} {
+		var opts []benchflix.Option
+
+		if instrument {
+			opts = append(opts, benchflix.WithInstrumentation())
+		}
+
+		db := sql.OpenDB(benchflix.Must(benchflix.NewOptions(opts...).Connector(sleepDriver{}, "")))
+
+		query := func(ctx context.Context, rows int) (int, error) {
+			time.Sleep(2 * time.Millisecond)
+
+			r, err := db.QueryContext(ctx, "SELECT", rows)
+			if err != nil {
+				return 0, err
+			}
+
+			defer r.Close()
+
+			count := 0
+
+			for r.Next() {
+				var v int64
+
+				if err := r.Scan(&v); err != nil {
+					return 0, err
+				}
+
+				time.Sleep(time.Millisecond)
+
+				count++
+			}
+
+			return count, r.Err()
+		}
+
+		count, phases, err := benchflix.MeasurePhases(context.Background(), 3, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count != 3 {
+			t.Errorf("rows: got %d, want 3", count)
+		}
+
+		// Without instrumentation everything is build time.
+		if !instrument {
+			if phases.DB != 0 || phases.Scan != 0 {
+				t.Errorf("uninstrumented phases: got %+v", phases)
+			}
+
+			continue
+		}
+
+		// The driver sleeps 2ms per query and 1ms per row.
+		if phases.Build < 2*time.Millisecond || phases.DB < 5*time.Millisecond || phases.Scan < 3*time.Millisecond {
+			t.Errorf("phases: got %+v", phases)
+		}
+	}
+
+	// GORM only reaches the instrumented connector with the ctx of the call.
+	repo := benchflix.Must(gormflix.OpenDB(sql.OpenDB(benchflix.Must(benchflix.NewOptions(benchflix.WithInstrumentation()).Connector(sleepDriver{}, ""))), 1, 2, time.Minute))
+
+	defer repo.(io.Closer).Close()
+
+	directors, phases, err := benchflix.MeasurePhases(context.Background(), benchflix.PreloadParams{Strategy: benchflix.PreloadAny, IDs: []int64{1, 2, 3}}, repo.QueryDirectors)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(directors) != 3 {
+		t.Errorf("gorm rows: got %d, want 3", len(directors))
+	}
+
+	if phases.DB < 5*time.Millisecond {
+		t.Errorf("gorm phases: got %+v", phases)
+	}
+}
+
+type sleepDriver struct{}
+
+func (sleepDriver) Open(string) (driver.Conn, error) {
+	return sleepConn{}, nil
+}
+
+type sleepConn struct{}
+
+func (sleepConn) Prepare(string) (driver.Stmt, error) { return sleepStmt{}, nil }
+func (sleepConn) Close() error                        { return nil }
+func (sleepConn) Begin() (driver.Tx, error)           { return nil, errors.ErrUnsupported }
+
+// QueryContext returns as many rows as the first arg says: a number gives a v
+// column, an array like {1,2,3} of movie ids gives movie_id and directors.
+func (sleepConn) QueryContext(_ context.Context, _ string, args []driver.NamedValue) (driver.Rows, error) {
+	time.Sleep(2 * time.Millisecond)
+
+	switch v := args[0].Value.(type) {
+	case int64:
+		return &sleepRows{n: v, columns: []string{"v"}}, nil
+	case string:
+		return &sleepRows{n: int64(strings.Count(v, ",") + 1), columns: []string{"movie_id", "directors"}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported arg %T", v)
+	}
+}
+
+type sleepStmt struct{}
+
+func (sleepStmt) Close() error                               { return nil }
+func (sleepStmt) NumInput() int                              { return -1 }
+func (sleepStmt) Exec([]driver.Value) (driver.Result, error) { return nil, errors.ErrUnsupported }
+func (sleepStmt) Query([]driver.Value) (driver.Rows, error)  { return nil, errors.ErrUnsupported }
+
+func (sleepStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return sleepConn{}.QueryContext(ctx, "", args)
+}
+
+type sleepRows struct {
+	n       int64
+	columns []string
+}
+
+func (r *sleepRows) Columns() []string { return r.columns }
+func (*sleepRows) Close() error        { return nil }
+
+func (r *sleepRows) Next(dest []driver.Value) error {
+	if r.n == 0 {
+		return io.EOF
+	}
+
+	time.Sleep(time.Millisecond)
+
+	dest[0] = r.n
+	r.n--
+
+	if len(dest) > 1 {
+		dest[1] = "{Director}"
+	}
+
+	return nil
+}
+
+func TestDetails(t *testing.T) {
+	if testing.Short() {
+		t.Skip("requires docker")
+	}
+
+	LoadParams()
+
+	expected := make(map[int64]benchflix.Movie, len(benchflix.Movies))
+
+	for _, m := range benchflix.Movies {
+		expected[m.ID] = m
+	}
+
+	for _, f := range Frameworks {
+		t.Run(f.Name, func(t *testing.T) {
+			conn, resource := benchflix.InitializePostgres(f.Name)
+
+			defer resource.Close()
+
+			repo := OpenRepository(t, f, conn)
+
+			for _, q := range []struct {
+				Name  string
+				Query func(context.Context, benchflix.ListParams) ([]benchflix.Movie, error)
+			}{
+				{"Details", repo.QueryDetails},
+				{"DetailsNull", repo.QueryDetailsNull},
+			} {
+				t.Run(q.Name, func(t *testing.T) {
+					var rows, nulls int
+
+					for _, params := range ListParams[:100] {
+						movies, err := q.Query(context.Background(), params)
+						if err == benchflix.ErrSkip {
+							t.SkipNow()
+						}
+
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						for _, m := range movies {
+							want := expected[m.ID]
+
+							if !reflect.DeepEqual(m.Runtime, want.Runtime) {
+								t.Errorf("movie %d: runtime %v, want %v", m.ID, m.Runtime, want.Runtime)
+							}
+
+							if !reflect.DeepEqual(m.Budget, want.Budget) {
+								t.Errorf("movie %d: budget %v, want %v", m.ID, m.Budget, want.Budget)
+							}
+
+							if !reflect.DeepEqual(m.Tagline, want.Tagline) {
+								t.Errorf("movie %d: tagline %v, want %v", m.ID, m.Tagline, want.Tagline)
+							}
+
+							if !reflect.DeepEqual(m.Metadata, want.Metadata) {
+								t.Errorf("movie %d: metadata %+v, want %+v", m.ID, m.Metadata, want.Metadata)
+							}
+
+							if m.Runtime == nil || m.Budget == nil || m.Tagline == nil || m.Metadata == nil {
+								nulls++
+							}
+
+							rows++
+						}
+					}
+
+					t.Logf("%d rows checked, %d with NULL columns", rows, nulls)
+				})
+			}
+		})
+	}
+}
+
+func TestMovie(t *testing.T) {
+	if testing.Short() {
+		t.Skip("requires docker")
+	}
+
+	for _, f := range Frameworks {
+		t.Run(f.Name, func(t *testing.T) {
+			conn, resource := benchflix.InitializePostgres(f.Name)
+
+			defer resource.Close()
+
+			repo := OpenRepository(t, f, conn)
+
+			for _, want := range benchflix.Movies[:100] {
+				movie, err := repo.QueryMovie(context.Background(), want.ID)
+				if err == benchflix.ErrSkip {
+					t.SkipNow()
+				}
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if movie.ID != want.ID || movie.Title != want.Title {
+					t.Errorf("movie %d: got %d %q, want %q", want.ID, movie.ID, movie.Title, want.Title)
+				}
+			}
+
+			if _, err := repo.QueryMovie(context.Background(), -1); !errors.Is(err, benchflix.ErrNotFound) {
+				t.Errorf("missing movie: got %v, want %v", err, benchflix.ErrNotFound)
+			}
+		})
+	}
+}
+
+// NamedRepository is the repository of a framework.
+type NamedRepository struct {
+	Name       string
+	Repository benchflix.Repository
+}
+
+// TestEqual checks that every adapter returns the same results, all adapters
+// share one database.
+func TestEqual(t *testing.T) {
+	if testing.Short() {
+		t.Skip("requires docker")
+	}
+
+	LoadParams()
+
+	conn, resource := benchflix.InitializePostgres("Equal")
+
+	defer resource.Close()
+
+	var repos []NamedRepository
+
+	for _, f := range Frameworks {
+		repos = append(repos, NamedRepository{f.Name, OpenRepository(t, f, conn)})
+	}
+
+	t.Run("Search", func(t *testing.T) {
+		Same(t, repos, benchflix.Repository.QuerySearch, sample(SearchParams))
+	})
+
+	t.Run("Websearch", func(t *testing.T) {
+		Same(t, repos, benchflix.Repository.QuerySearch, sample(WebsearchParams))
+	})
+
+	t.Run("Facets", func(t *testing.T) {
+		Same(t, repos, settledFacets, sample(DashboardParams))
+	})
+
+	t.Run("FacetsBatch", func(t *testing.T) {
+		facets := slices.Clone(repos)
+
+		for _, repo := range repos {
+			facets = append(facets, NamedRepository{repo.Name + "/Batch", batchRepository{repo.Repository}})
+		}
+
+		Same(t, facets, settledFacets, sample(DashboardParams))
+	})
+
+	t.Run("TopRated", func(t *testing.T) {
+		Same(t, repos, benchflix.Repository.QueryTopRated, sample(TopParams))
+	})
+
+	t.Run("Collaborators", func(t *testing.T) {
+		Same(t, repos, benchflix.Repository.QueryCollaborators, sample(GraphParams))
+	})
+
+	t.Run("Wide", func(t *testing.T) {
+		Same(t, repos, benchflix.Repository.QueryWide, sample(WideParams))
+	})
+
+	t.Run("Directors", func(t *testing.T) {
+		var preloads []NamedRepository
+
+		for _, strategy := range []benchflix.PreloadStrategy{benchflix.PreloadAny, benchflix.PreloadUnnest, benchflix.PreloadTempTable, benchflix.PreloadBatch} {
+			for _, repo := range repos {
+				preloads = append(preloads, NamedRepository{repo.Name + "/" + string(strategy), preloadRepository{repo.Repository, strategy}})
+			}
+		}
+
+		params := make([]benchflix.PreloadParams, 20)
+
+		for i := range params {
+			params[i].IDs = PreloadIDs[i*1000 : (i+1)*1000]
+		}
+
+		Same(t, preloads, benchflix.Repository.QueryDirectors, params)
+	})
+
+	t.Run("ListNPlusOne", func(t *testing.T) {
+		lists := slices.Clone(repos)
+
+		for _, repo := range repos {
+			lists = append(lists, NamedRepository{repo.Name + "/NPlusOne", listRepository{repo.Repository, benchflix.Repository.QueryListNPlusOne}})
+		}
+
+		Same(t, lists, settledList, sample(ListParams))
+	})
+
+	t.Run("ListJSON", func(t *testing.T) {
+		lists := slices.Clone(repos)
+
+		for _, repo := range repos {
+			lists = append(lists, NamedRepository{repo.Name + "/JSON", listRepository{repo.Repository, benchflix.Repository.QueryListJSON}})
+		}
+
+		Same(t, lists, settledList, sample(ListParams))
+	})
+}
+
+// listRepository answers QueryListPreload with another list query, so that
+// it can be compared to the preload of every adapter.
+type listRepository struct {
+	benchflix.Repository
+	list func(benchflix.Repository, context.Context, benchflix.ListParams) ([]benchflix.Movie, error)
+}
+
+func (r listRepository) QueryListPreload(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	return r.list(r.Repository, ctx, params)
+}
+
+// settledList returns the settled movies of QueryListPreload.
+func settledList(r benchflix.Repository, ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	movies, err := r.QueryListPreload(ctx, params)
+
+	return settle(movies, params.Limit, func(m benchflix.Movie) any { return m.Rating }), err
+}
+
+// batchRepository answers QueryFacets with QueryFacetsBatch.
+type batchRepository struct {
+	benchflix.Repository
+}
+
+func (r batchRepository) QueryFacets(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	return r.QueryFacetsBatch(ctx, params)
+}
+
+// settledFacets returns the facets of QueryFacets with settled movies.
+func settledFacets(r benchflix.Repository, ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	facets, err := r.QueryFacets(ctx, params)
+
+	facets.Movies = settle(facets.Movies, params.Limit, dashboardKey(params))
+
+	return facets, err
+}
+
+// settle makes a page of movies comparable across adapters. The queries order
+// by a single column, so ties come back in any order and, at the limit, as
+// different movies: settle sorts ties by id and drops the ties at the limit.
+func settle(movies []benchflix.Movie, limit uint64, key func(benchflix.Movie) any) []benchflix.Movie {
+	if limit < 1 || limit > 1000 {
+		limit = 1000
+	}
+
+	start := 0
+
+	for i := 1; i <= len(movies); i++ {
+		if i < len(movies) && key(movies[i]) == key(movies[start]) {
+			continue
+		}
+
+		if i == len(movies) && uint64(len(movies)) == limit {
+			return movies[:start]
+		}
+
+		slices.SortFunc(movies[start:i], func(a, b benchflix.Movie) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+
+		start = i
+	}
+
+	return movies
+}
+
+// dashboardKey returns the column a dashboard query sorts by.
+func dashboardKey(params benchflix.DashboardParams) func(benchflix.Movie) any {
+	switch params.Sort {
+	case "title":
+		return func(m benchflix.Movie) any { return m.Title }
+	case "added_at":
+		return func(m benchflix.Movie) any { return m.AddedAt.Unix() }
+	default:
+		return func(m benchflix.Movie) any { return m.Rating }
+	}
+}
+
+// preloadRepository loads directors with one strategy, sorted by movie since
+// only some strategies keep the order of the ids.
+type preloadRepository struct {
+	benchflix.Repository
+	strategy benchflix.PreloadStrategy
+}
+
+func (r preloadRepository) QueryDirectors(ctx context.Context, params benchflix.PreloadParams) ([]benchflix.MovieDirectors, error) {
+	params.Strategy = r.strategy
+
+	directors, err := r.Repository.QueryDirectors(ctx, params)
+
+	slices.SortFunc(directors, func(a, b benchflix.MovieDirectors) int {
+		return cmp.Compare(a.MovieID, b.MovieID)
+	})
+
+	return directors, err
+}
+
+// sample returns the params the equality tests compare.
+func sample[P any](params []P) []P {
+	return params[:min(20, len(params))]
+}
+
+// Same fails t unless every repository returns for each of params what the
+// first repository supporting query returns.
+func Same[P, R any](t *testing.T, repos []NamedRepository, query func(benchflix.Repository, context.Context, P) (R, error), params []P) {
+	t.Helper()
+
+	var (
+		reference string
+		want      []R
+	)
+
+	for _, repo := range repos {
+		got := make([]R, len(params))
+
+		for i, p := range params {
+			result, err := query(repo.Repository, context.Background(), p)
+			if err == benchflix.ErrSkip {
+				break
+			}
+
+			if err != nil {
+				t.Fatalf("%s: params %d: %v", repo.Name, i, err)
+			}
+
+			got[i] = result
+
+			if want == nil {
+				continue
+			}
+
+			if !approxEqual(reflect.ValueOf(result), reflect.ValueOf(want[i])) {
+				t.Errorf("%s: params %d differs from %s: %s", repo.Name, i, reference, difference(reflect.ValueOf(result), reflect.ValueOf(want[i])))
+			}
+		}
+
+		if want == nil && !reflect.ValueOf(got[0]).IsZero() {
+			reference, want = repo.Name, got
+		}
+	}
+
+	if want == nil {
+		t.Fatal("no repository returned results")
+	}
+}
+
+// approxEqual is reflect.DeepEqual, except that floats only need to agree to
+// float4 precision and nil and empty slices are equal. Adapters read float4 and
+// numeric columns as text or binary and aggregate no rows to NULL or '{}'.
+func approxEqual(a, b reflect.Value) bool {
+	if a.Type() == reflect.TypeFor[time.Time]() {
+		return a.Interface().(time.Time).Equal(b.Interface().(time.Time))
+	}
+
+	switch a.Kind() {
+	case reflect.Float32, reflect.Float64:
+		x, y := a.Float(), b.Float()
+
+		return x == y || math.Abs(x-y) <= 1e-6*max(math.Abs(x), math.Abs(y))
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+
+		for i := range a.Len() {
+			if !approxEqual(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+
+		return approxEqual(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := range a.NumField() {
+			if !approxEqual(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+}
+
+// difference describes the first row in which got and want differ.
+func difference(got, want reflect.Value) string {
+	if got.Kind() != reflect.Slice {
+		return fmt.Sprintf("got %+v, want %+v", got, want)
+	}
+
+	for i := range min(got.Len(), want.Len()) {
+		if !approxEqual(got.Index(i), want.Index(i)) {
+			return fmt.Sprintf("row %d: got %+v, want %+v", i, got.Index(i), want.Index(i))
+		}
+	}
+
+	return fmt.Sprintf("got %d rows, want %d", got.Len(), want.Len())
+}
+
+func TestWarmup(t *testing.T) {
+	noop := func(int) error { return nil }
+
+	count, err := benchflix.ParseWarmup("count:1200")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	warmed, err := count.Run(4, noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if warmed.Calls != 1200 || len(warmed.Curve) != 3 {
+		t.Fatalf("count: %d calls, %d windows", warmed.Calls, len(warmed.Curve))
+	}
+
+	steady, err := benchflix.ParseWarmup("steady:10:0.2:1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// latency keeps dropping, but ever more slowly
+	warmed, err = steady.Run(1, func(i int) error {
+		time.Sleep(4 * time.Millisecond / time.Duration(1+i/10))
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !warmed.Steady || warmed.Calls < 50 || warmed.Calls >= 1000 {
+		t.Fatalf("steady: %d calls, steady %t, curve %v", warmed.Calls, warmed.Steady, warmed.Curve)
+	}
+
+	duration, err := benchflix.ParseWarmup("duration:20ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	warmed, err = duration.Run(2, func(int) error {
+		time.Sleep(time.Millisecond)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if warmed.Duration < 20*time.Millisecond {
+		t.Fatalf("duration: %s", warmed.Duration)
+	}
+
+	for _, spec := range []string{"", "count:x", "count:-1", "steady:10:0.1", "steady:0:0.1:100", "steady:10:-0.1:100", "steady:10:NaN:100", "steady:10:0.1:-1", "duration:1", "duration:-1s"} {
+		if _, err := benchflix.ParseWarmup(spec); err == nil {
+			t.Fatalf("%q: want error", spec)
+		}
+	}
+}
+
+func TestPoolStats(t *testing.T) {
+	prev := benchflix.PoolStats{HasAcquires: true, Acquires: 10, EmptyAcquires: 2, AcquireDuration: time.Second, NewConns: 3, ClosedConns: 1}
+	now := benchflix.PoolStats{HasAcquires: true, Acquires: 14, EmptyAcquires: 3, AcquireDuration: time.Second + 8*time.Millisecond, NewConns: 5, ClosedConns: 1}
+
+	delta := now.Sub(prev)
+	if delta != (benchflix.PoolStats{HasAcquires: true, Acquires: 4, EmptyAcquires: 1, AcquireDuration: 8 * time.Millisecond, NewConns: 2}) {
+		t.Fatalf("sub: %+v", delta)
+	}
+
+	if got := delta.NsPerAcquire(); got != float64(2*time.Millisecond) {
+		t.Fatalf("ns/acquire: got %g", got)
+	}
+
+	if got := (benchflix.PoolStats{}).NsPerAcquire(); got != 0 {
+		t.Fatalf("ns/acquire without acquires: got %g", got)
+	}
+
+	// two concurrent queries on a single connection, one has to wait
+	db := sql.OpenDB(benchflix.Must(benchflix.NewOptions().Connector(sleepDriver{}, "")))
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	before := benchflix.SQLPoolStats(db)
+
+	var group sync.WaitGroup
+
+	for range 2 {
+		group.Add(1)
+
+		go func() {
+			defer group.Done()
+
+			var v int64
+			if err := db.QueryRowContext(context.Background(), "SELECT $1", int64(1)).Scan(&v); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	group.Wait()
+
+	delta = benchflix.SQLPoolStats(db).Sub(before)
+	if !delta.HasWaits || delta.HasAcquires || delta.Waits != 1 || delta.WaitDuration <= 0 || delta.NewConns != 1 {
+		t.Fatalf("sql: %+v", delta)
+	}
+}
+
+func TestStatementStats(t *testing.T) {
+	before := []benchflix.StatementStats{
+		{Query: "a", Calls: 2, Plans: 2, Rows: 4, TotalExecTime: 2, TotalPlanTime: 1, SharedBlksHit: 10, SharedBlksRead: 1},
+		{Query: "b", Calls: 1, Plans: 1, Rows: 1, TotalExecTime: 3},
+		{Query: "d", Calls: 5, Plans: 5, TotalExecTime: 5},
+	}
+
+	after := []benchflix.StatementStats{
+		{Query: "a", Calls: 5, Plans: 5, Rows: 10, TotalExecTime: 8, TotalPlanTime: 2.5, SharedBlksHit: 25, SharedBlksRead: 1},
+		{Query: "b", Calls: 1, Plans: 1, Rows: 1, TotalExecTime: 3},
+		{Query: "c", Calls: 1, Plans: 1, Rows: 1, TotalExecTime: 10, MeanExecTime: 10},
+		// a second row of the same query, e.g. of another user
+		{Query: "a", Calls: 1, Rows: 2, TotalExecTime: 1},
+		// reset in between
+		{Query: "d", Calls: 1, Plans: 1, TotalExecTime: 1},
+	}
+
+	diff := benchflix.DiffStatementStats(before, after)
+
+	want := []benchflix.StatementStats{
+		{Query: "c", Calls: 1, Plans: 1, Rows: 1, TotalExecTime: 10, MeanExecTime: 10},
+		{Query: "a", Calls: 4, Plans: 3, Rows: 8, TotalExecTime: 7, MeanExecTime: 1.75, TotalPlanTime: 1.5, MeanPlanTime: 0.5, SharedBlksHit: 15},
+	}
+
+	if !reflect.DeepEqual(diff, want) {
+		t.Fatalf("diff: got %+v, want %+v", diff, want)
+	}
+
+	sum := benchflix.SumStatementStats(diff)
+	if sum != (benchflix.StatementStats{Query: "c", Calls: 5, Plans: 4, Rows: 9, TotalExecTime: 17, MeanExecTime: 3.4, TotalPlanTime: 1.5, MeanPlanTime: 0.375, SharedBlksHit: 15}) {
+		t.Fatalf("sum: %+v", sum)
+	}
+
+	if diff := benchflix.DiffStatementStats(after, after); len(diff) != 0 {
+		t.Fatalf("diff of equal snapshots: %+v", diff)
+	}
+
+	if sum := benchflix.SumStatementStats(nil); sum != (benchflix.StatementStats{}) {
+		t.Fatalf("sum of nothing: %+v", sum)
+	}
+}
+
+// stack is a sample of a profile, the locations are innermost first and so are
+// the inlined functions of a location.
+type stack struct {
+	value     int64
+	locations [][]string
+}
+
+func stackProfile(stacks ...stack) *profile.Profile {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{{Type: "alloc_space", Unit: "bytes"}},
+		PeriodType: &profile.ValueType{Type: "space", Unit: "bytes"},
+	}
+	functions := map[string]*profile.Function{}
+
+	for _, s := range stacks {
+		sample := &profile.Sample{Value: []int64{s.value}}
+
+		for _, lines := range s.locations {
+			loc := &profile.Location{ID: uint64(len(p.Location) + 1)}
+
+			for _, name := range lines {
+				f := functions[name]
+				if f == nil {
+					f = &profile.Function{ID: uint64(len(p.Function) + 1), Name: name}
+					functions[name] = f
+					p.Function = append(p.Function, f)
+				}
+
+				loc.Line = append(loc.Line, profile.Line{Function: f})
+			}
+
+			p.Location = append(p.Location, loc)
+			sample.Location = append(sample.Location, loc)
+		}
+
+		p.Sample = append(p.Sample, sample)
+	}
+
+	return p
+}
+
+func TestProfiles(t *testing.T) {
+	const (
+		malloc = "runtime.mallocgc"
+		pgx    = "github.com/jackc/pgx/v5.(*Conn).Query"
+		sqlt   = "github.com/go-sqlt/sqlt.(*Statement).Exec"
+		repo   = "github.com/go-sqlt/benchflix/sqltflix.Repository.QueryList"
+		exec   = "github.com/go-sqlt/benchflix_test.ExecBenchmark"
+	)
+
+	before := stackProfile(
+		stack{10, [][]string{{malloc}, {pgx}, {exec}}},
+		stack{5, [][]string{{malloc}, {exec}}},
+	)
+
+	after := stackProfile(
+		stack{25, [][]string{{malloc}, {pgx}, {exec}}},
+		stack{5, [][]string{{malloc}, {exec}}},
+		// sqlt inlined into the repository
+		stack{7, [][]string{{malloc}, {sqlt, repo}, {exec}}},
+		stack{3, [][]string{{repo}, {exec}}},
+		stack{2, [][]string{{exec}}},
+	)
+
+	delta, err := benchflix.DiffProfiles(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(delta.Sample) != 4 {
+		t.Fatalf("delta: got %d samples, want 4", len(delta.Sample))
+	}
+
+	if len(before.Sample) != 2 || before.Sample[0].Value[0] != 10 {
+		t.Fatal("delta changed the profile before")
+	}
+
+	grouped := benchflix.GroupProfile(delta, 0)
+
+	want := benchflix.GroupedProfile{
+		Total:  27,
+		Groups: map[string]int64{"driver": 15, "framework": 7, "benchflix": 3, benchflix.OtherGroup: 2},
+		Functions: map[string]map[string]int64{
+			"driver":             {pgx: 15},
+			"framework":          {sqlt: 7},
+			"benchflix":          {repo: 3},
+			benchflix.OtherGroup: {exec: 2},
+		},
+	}
+
+	if !reflect.DeepEqual(grouped, want) {
+		t.Fatalf("grouped: got %+v, want %+v", grouped, want)
+	}
+}
+
+func TestRunner(t *testing.T) {
+	var (
+		repo    = &countingRepository{}
+		params  = []benchflix.ListParams{{Search: "a"}, {Search: "b"}}
+		reports atomic.Int64
+	)
+
+	runner := benchflix.Runner{
+		Concurrency: 3,
+		Duration:    100 * time.Millisecond,
+		Warmup:      benchflix.Warmup{Mode: benchflix.WarmupCount, Count: 100, Window: 10},
+		Interval:    20 * time.Millisecond,
+		Report:      func(benchflix.RunResult) { reports.Add(1) },
+	}
+
+	result, err := benchflix.Run(context.Background(), runner, repo, benchflix.Repository.QueryList, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.WarmupCalls != 100 || result.Calls == 0 || result.Calls+101 != repo.calls.Load() {
+		t.Fatalf("calls: warmup %d, measured %d, total %d", result.WarmupCalls, result.Calls, repo.calls.Load())
+	}
+
+	if result.Elapsed < runner.Duration || result.P50 > result.P99 || result.P99 > result.Max {
+		t.Fatalf("timing: %+v", result)
+	}
+
+	if reports.Load() == 0 {
+		t.Fatal("no intermediate reports")
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	m, err := benchflix.LoadMatrix("matrix.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+
+	for _, f := range m.Frameworks {
+		names = append(names, f.Name)
+	}
+
+	// the checked in matrix reproduces the defaults of the harness
+	for i, f := range Frameworks {
+		if i >= len(names) || names[i] != f.Name {
+			t.Fatalf("frameworks: got %v", names)
+		}
+	}
+
+	if !slices.Equal(m.Sizes, ParamSizes) || !slices.Equal(m.PreloadSizes, PreloadSizes) || !slices.Equal(m.CacheSizes, CacheSizes) {
+		t.Fatalf("sizes: %+v", m)
+	}
+
+	if !slices.Equal(m.Pools, Pools) || time.Duration(m.IdleTimeout) != IdleTimeout {
+		t.Fatalf("pool: %+v", m)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+
+	data := `{"frameworks": [{"adapter": "jdbc"}, {"adapter": "pgx", "expression_size": 1}], "szenarios": ["Lsit"], "sizes": [0], "pools": [{"min": 3, "max": 6}, {"min": 6, "max": 3}, {"min": 3, "max": 6}], "warmup": "forever", "idle_timeout": "2m", "timeout": "-1m", "settle": "-1s"}`
+
+	if err = os.WriteFile(invalid, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = benchflix.LoadMatrix(invalid)
+	if err == nil {
+		t.Fatal("want error")
+	}
+
+	for _, want := range []string{"jdbc", "expression_size", "Lsit", "size: 0", "pools[1]: invalid connections", "pools[2]: duplicate", "forever", "invalid timeout: -1m0s", "invalid settle: -1s"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestCLI(t *testing.T) {
+	dir := t.TempDir()
+
+	base := filepath.Join(dir, "base.bench")
+	results := filepath.Join(dir, "new.bench")
+
+	if err := errors.Join(
+		os.WriteFile(base, []byte("Benchmark/SQL/List/100-8 \t 1000\t 1000 ns/op\t 100 B/op\t 10 allocs/op\n"), 0o644),
+		os.WriteFile(results, []byte("Benchmark/SQL/List/100-8 \t 1000\t 1200 ns/op\t 100 B/op\t 10 allocs/op\n"), 0o644),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		Args []string
+		Code int
+	}{
+		{[]string{}, cli.ExitUsage},
+		{[]string{"unknown"}, cli.ExitUsage},
+		{[]string{"compare"}, cli.ExitUsage},
+		{[]string{"compare", "-base", base, "-results", base}, cli.ExitOK},
+		{[]string{"compare", "-base", base, "-results", results}, cli.ExitRegression},
+		{[]string{"compare", "-base", base, "-results", results, "-threshold", "0.5"}, cli.ExitOK},
+		{[]string{"compare", "-base", base, "-results", filepath.Join(dir, "missing.bench")}, cli.ExitError},
+		{[]string{"compare", "-base", base, "-results", results, "-label", "pool=1-2"}, cli.ExitOK},
+		{[]string{"compare", "-base", base, "-results", results, "-label", "pool"}, cli.ExitUsage},
+		{[]string{"params", "-size", "3", "-params", filepath.Join(dir, "params.json")}, cli.ExitOK},
+		{[]string{"params", "extra"}, cli.ExitUsage},
+	} {
+		if code := cli.Main(c.Args); code != c.Code {
+			t.Errorf("%v: got exit code %d, want %d", c.Args, code, c.Code)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "params.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var params []benchflix.DashboardParams
+
+	if err = json.Unmarshal(data, &params); err != nil || len(params) != 3 {
+		t.Fatalf("params: %d, %v", len(params), err)
+	}
+}
+
+func TestResults(t *testing.T) {
+	text := "goos: linux\nBenchmark/SQL/List/100-8 \t 1000\t 1500 ns/op\t 100 B/op\t 10 allocs/op\t 12 warmup-calls\n"
+
+	data, err := json.Marshal(benchflix.Results{
+		Version:     benchflix.ResultsVersion,
+		Environment: benchflix.NewEnvironment(),
+		Benchmarks: []benchflix.Result{{
+			Name:    "Benchmark/SQL/List/100",
+			N:       1000,
+			Metrics: map[string]float64{"ns/op": 1500, "B/op": 100, "allocs/op": 10, "warmup-calls": 12},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := benchflix.Params{
+		NsPerOp:     []float64{1500},
+		BytesPerOp:  []float64{100},
+		AllocsPerOp: []float64{10},
+		WarmupCalls: []float64{12},
+		Metrics:     map[string][]float64{"ns/op": {1500}, "B/op": {100}, "allocs/op": {10}, "warmup-calls": {12}},
+		Labels:      []map[string]string{{"gomaxprocs": "8"}},
+	}
+
+	events := strings.Join([]string{
+		`{"Action":"start","Package":"github.com/go-sqlt/benchflix"}`,
+		`{"Action":"output","Output":"goos: linux\n"}`,
+		`{"Action":"output","Test":"Benchmark/SQL/List/100","Output":"Benchmark/SQL/List/100-8 \t"}`,
+		`{"Action":"output","Test":"Benchmark/SQL/List/100","Output":" 1000\t 1500 ns/op\t 100 B/op\t 10 allocs/op\t 12 warmup-calls\n"}`,
+		`{"Action":"pass","Package":"github.com/go-sqlt/benchflix"}`,
+	}, "\n")
+
+	results := strings.Replace(string(data), `"gomaxprocs":`+strconv.Itoa(runtime.GOMAXPROCS(0)), `"gomaxprocs":8`, 1)
+
+	for _, input := range []string{text, events, "\n" + results} {
+		bench, err := benchflix.ReadAll(strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := bench.SQL.List.Hundred; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	}
+
+	bench, err := benchflix.ReadAll(strings.NewReader("Benchmark/SQL/List/1000/conns=6 \t 10\t 20 ns/op\t 3 roundtrips/op\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := bench.SQL.List.Thousand; got.Metrics["roundtrips/op"][0] != 3 || got.Labels[0]["conns"] != "6" {
+		t.Errorf("got %+v", got)
+	}
+
+	pools := "Benchmark/SQL/pool=3-6/List/100 \t 10\t 20 ns/op\nBenchmark/SQL/pool=1-2/List/100 \t 10\t 30 ns/op\n"
+
+	bench, err = benchflix.ReadAllWhere(strings.NewReader(pools), map[string]string{"pool": "1-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := bench.SQL.List.Hundred.NsPerOp; !slices.Equal(got, []float64{30}) {
+		t.Errorf("pool=1-2: got %v", got)
+	}
+
+	for _, c := range []struct{ Input, Want string }{
+		{`{"version": 2}`, "unsupported version"},
+		{"goos: linux\nBenchmark/SQL/List/100 \t 10\t 20 ns/op\t 3\n", "line 2: "},
+		{"Benchmark/SQL/List/100 \t 10\t fast ns/op\n", "line 1: "},
+		{"Benchmark/SQL/Unknown/100 \t 10\t 20 ns/op\n", "invalid szenario"},
+		{`{"Action":"output","Output":"x\n"}` + "\n" + `{"Action":"output","Output":"Benchmark/SQL/List/7 1 2 ns/op\n"}`, "line 2: "},
+		{`{"Action":"output","Output":"x\n"}` + "\n" + `{"Action":"output","Output":"Benchmark/SQL/List/7 1 2 ns/op"}`, "line 2: "},
+		{"BenchmarkMiddleware/Metrics-8 \t 1000\t 50 ns/op\n", "not a result of Benchmark"},
+		{"\n\n  \nBenchmark/SQL/List/100 \t 10\t fast ns/op\n", "line 4: "},
+		{"\n\n" + `{"Action":"output","Output":"x\n"}` + "\n" + `{"Action":"output","Output":"Benchmark/SQL/List/7 1 2 ns/op\n"}`, "line 4: "},
+	} {
+		if _, err = benchflix.ReadAll(strings.NewReader(c.Input)); err == nil || !strings.Contains(err.Error(), c.Want) {
+			t.Errorf("%q: got %v, want %q", c.Input, err, c.Want)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package benchflix
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CachedRepository is a read-through cache in front of a Repository. Results of
+// the scenarios keyed by ListParams or DashboardParams are kept for ttl, at most
+// size entries are cached and identical in-flight queries share one call. All
+// other scenarios pass through. Cached results are shared between callers and
+// must not be modified. Listen keeps the cache in line with writes to the
+// database.
+type CachedRepository struct {
+	Repository
+
+	ttl  time.Duration
+	size int
+
+	mu         sync.Mutex
+	entries    map[cacheKey]*list.Element
+	lru        *list.List
+	calls      map[cacheKey]*cacheCall
+	generation uint64
+
+	hits, misses atomic.Int64
+}
+
+type cacheKey struct {
+	szenario string
+	params   any
+}
+
+// cacheCall is a query in flight, the callers of the same key wait for done.
+type cacheCall struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	value   any
+	expires time.Time
+}
+
+func NewCachedRepository(repo Repository, ttl time.Duration, size int) *CachedRepository {
+	return &CachedRepository{
+		Repository: repo,
+		ttl:        ttl,
+		size:       size,
+		entries:    make(map[cacheKey]*list.Element, size),
+		lru:        list.New(),
+		calls:      make(map[cacheKey]*cacheCall),
+	}
+}
+
+func (c *CachedRepository) QueryList(ctx context.Context, params ListParams) ([]Movie, error) {
+	return cached(ctx, c, "List", params, c.Repository.QueryList)
+}
+
+func (c *CachedRepository) QueryListPreload(ctx context.Context, params ListParams) ([]Movie, error) {
+	return cached(ctx, c, "ListPreload", params, c.Repository.QueryListPreload)
+}
+
+func (c *CachedRepository) QueryListNPlusOne(ctx context.Context, params ListParams) ([]Movie, error) {
+	return cached(ctx, c, "ListNPlusOne", params, c.Repository.QueryListNPlusOne)
+}
+
+func (c *CachedRepository) QueryListJSON(ctx context.Context, params ListParams) ([]Movie, error) {
+	return cached(ctx, c, "ListJSON", params, c.Repository.QueryListJSON)
+}
+
+func (c *CachedRepository) QueryDashboard(ctx context.Context, params DashboardParams) ([]Movie, error) {
+	return cached(ctx, c, "Dashboard", params, c.Repository.QueryDashboard)
+}
+
+func (c *CachedRepository) QueryDashboardPreload(ctx context.Context, params DashboardParams) ([]Movie, error) {
+	return cached(ctx, c, "DashboardPreload", params, c.Repository.QueryDashboardPreload)
+}
+
+func (c *CachedRepository) QueryDetails(ctx context.Context, params ListParams) ([]Movie, error) {
+	return cached(ctx, c, "Details", params, c.Repository.QueryDetails)
+}
+
+func (c *CachedRepository) QueryDetailsNull(ctx context.Context, params ListParams) ([]Movie, error) {
+	return cached(ctx, c, "DetailsNull", params, c.Repository.QueryDetailsNull)
+}
+
+func (c *CachedRepository) QueryFacets(ctx context.Context, params DashboardParams) (FacetResult, error) {
+	return cached(ctx, c, "Facets", params, c.Repository.QueryFacets)
+}
+
+func (c *CachedRepository) QueryFacetsBatch(ctx context.Context, params DashboardParams) (FacetResult, error) {
+	return cached(ctx, c, "FacetsBatch", params, c.Repository.QueryFacetsBatch)
+}
+
+// Invalidate drops all cached results, queries still in flight are not stored
+// afterwards.
+func (c *CachedRepository) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.lru.Init()
+	c.generation++
+}
+
+// ResetStats starts counting hits and misses anew, e.g. after a warmup.
+func (c *CachedRepository) ResetStats() {
+	c.hits.Store(0)
+	c.misses.Store(0)
+}
+
+// HitRatio returns the share of lookups answered from the cache since it was
+// created or its stats were reset.
+func (c *CachedRepository) HitRatio() float64 {
+	hits, misses := c.hits.Load(), c.misses.Load()
+
+	if hits+misses == 0 {
+		return 0
+	}
+
+	return float64(hits) / float64(hits+misses)
+}
+
+// Listen invalidates the cache whenever the triggers of the schema announce a
+// write to movies, people or movie_directors on ChangesChannel. It returns once
+// listening and stops when ctx is done. If the connection is lost, the cache
+// can no longer see writes and stops caching.
+func (c *CachedRepository) Listen(ctx context.Context, conn string) error {
+	listener, err := pgx.Connect(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if _, err = listener.Exec(ctx, "LISTEN "+ChangesChannel); err != nil {
+		_ = listener.Close(context.Background())
+
+		return err
+	}
+
+	go func() {
+		defer listener.Close(context.Background())
+
+		for {
+			if _, err := listener.WaitForNotification(ctx); err != nil {
+				if ctx.Err() == nil {
+					c.mu.Lock()
+					c.size = 0
+					c.mu.Unlock()
+
+					c.Invalidate()
+				}
+
+				return
+			}
+
+			c.Invalidate()
+		}
+	}()
+
+	return nil
+}
+
+// cached answers from the cache or joins the call in flight for the same key.
+// The first caller runs the query without its cancellation, like the batches
+// of Loader, so that giving up does not fail the others waiting for it.
+func cached[P comparable, R any](ctx context.Context, c *CachedRepository, szenario string, params P, query func(context.Context, P) (R, error)) (R, error) {
+	var (
+		key  = cacheKey{szenario: szenario, params: params}
+		zero R
+	)
+
+	c.mu.Lock()
+
+	if value, ok := c.get(key); ok {
+		c.mu.Unlock()
+		c.hits.Add(1)
+
+		return value.(R), nil
+	}
+
+	c.misses.Add(1)
+
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+
+		if call.err != nil {
+			return zero, call.err
+		}
+
+		return call.value.(R), nil
+	}
+
+	call := &cacheCall{done: make(chan struct{})}
+	c.calls[key] = call
+	generation := c.generation
+
+	c.mu.Unlock()
+
+	result, err := query(context.WithoutCancel(ctx), params)
+
+	call.value, call.err = result, err
+
+	c.mu.Lock()
+
+	delete(c.calls, key)
+
+	if err == nil {
+		c.set(key, result, generation)
+	}
+
+	c.mu.Unlock()
+
+	close(call.done)
+
+	return result, err
+}
+
+// get and set require c.mu.
+func (c *CachedRepository) get(key cacheKey) (any, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+
+	if time.Now().After(entry.expires) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+
+		return nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+
+	return entry.value, true
+}
+
+func (c *CachedRepository) set(key cacheKey, value any, generation uint64) {
+	if generation != c.generation || c.size <= 0 {
+		return
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.value = value
+		entry.expires = time.Now().Add(c.ttl)
+		c.lru.MoveToFront(elem)
+
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{
+		key:     key,
+		value:   value,
+		expires: time.Now().Add(c.ttl),
+	})
+
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
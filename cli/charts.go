@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/snapshot-chromedp/render"
+	"github.com/go-sqlt/benchflix"
+	"github.com/montanaflynn/stats"
+)
+
+func renderCharts(o *Options, args []string) error {
+	if err := o.Parse(o.FlagSet("charts"), args); err != nil {
+		return err
+	}
+
+	b, err := o.ReadResults()
+	if err != nil {
+		return err
+	}
+
+	frameworks := o.frameworks(b)
+
+	for _, metric := range metrics {
+		for _, size := range []struct {
+			Name   string
+			Params func(benchflix.Szenario) benchflix.Params
+		}{
+			{"100", func(s benchflix.Szenario) benchflix.Params { return s.Hundred }},
+			{"1000", func(s benchflix.Szenario) benchflix.Params { return s.Thousand }},
+		} {
+			if err := renderChart(o, frameworks, size.Name+" Params "+metric.Name, func(s benchflix.Szenario) opts.BarData {
+				return opts.BarData{Value: ignoreErr(stats.Quartile(metric.Values(size.Params(s)))).Q2}
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, size := range []struct {
+		Name   string
+		Params func(benchflix.Strategy) benchflix.Params
+	}{
+		{"10", func(s benchflix.Strategy) benchflix.Params { return s.Ten }},
+		{"1000", func(s benchflix.Strategy) benchflix.Params { return s.Thousand }},
+		{"10000", func(s benchflix.Strategy) benchflix.Params { return s.TenThousand }},
+		{"50000", func(s benchflix.Strategy) benchflix.Params { return s.FiftyThousand }},
+	} {
+		for _, metric := range metrics {
+			if err := renderStrategyChart(o, frameworks, size.Name+" IDs Preload "+metric.Name, func(s benchflix.Strategy) opts.BarData {
+				return opts.BarData{Value: ignoreErr(stats.Quartile(metric.Values(size.Params(s)))).Q2}
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, size := range []struct {
+		Name   string
+		Params func(benchflix.Cache) benchflix.Params
+	}{
+		{"10", func(c benchflix.Cache) benchflix.Params { return c.Ten }},
+		{"100", func(c benchflix.Cache) benchflix.Params { return c.Hundred }},
+		{"1000", func(c benchflix.Cache) benchflix.Params { return c.Thousand }},
+	} {
+		for _, metric := range metrics {
+			if err := renderCacheChart(o, frameworks, size.Name+" Entries Cached "+metric.Name, func(c benchflix.Cache) opts.BarData {
+				return opts.BarData{Value: ignoreErr(stats.Quartile(metric.Values(size.Params(c)))).Q2}
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+var metrics = []struct {
+	Name   string
+	Values func(benchflix.Params) []float64
+}{
+	{"NsPerOp", func(p benchflix.Params) []float64 { return p.NsPerOp }},
+	{"BytesPerOp", func(p benchflix.Params) []float64 { return p.BytesPerOp }},
+	{"AllocsPerOp", func(p benchflix.Params) []float64 { return p.AllocsPerOp }},
+}
+
+func renderChart(o *Options, frameworks []namedFramework, title string, fn func(benchflix.Szenario) opts.BarData) error {
+	chart := newBar(title, frameworks)
+
+	for _, s := range szenarios {
+		var data []opts.BarData
+
+		for _, f := range frameworks {
+			data = append(data, fn(s.Get(f.Framework)))
+		}
+
+		chart.AddSeries(s.Name, data)
+	}
+
+	return snapshot(o, chart, title)
+}
+
+func renderStrategyChart(o *Options, frameworks []namedFramework, title string, fn func(benchflix.Strategy) opts.BarData) error {
+	chart := newBar(title, frameworks)
+
+	for _, s := range []struct {
+		Name string
+		Get  func(benchflix.Framework) benchflix.Strategy
+	}{
+		{"Any", func(f benchflix.Framework) benchflix.Strategy { return f.PreloadAny }},
+		{"Unnest", func(f benchflix.Framework) benchflix.Strategy { return f.PreloadUnnest }},
+		{"TempTable", func(f benchflix.Framework) benchflix.Strategy { return f.PreloadTempTable }},
+		{"Batch", func(f benchflix.Framework) benchflix.Strategy { return f.PreloadBatch }},
+	} {
+		var data []opts.BarData
+
+		for _, f := range frameworks {
+			data = append(data, fn(s.Get(f.Framework)))
+		}
+
+		chart.AddSeries(s.Name, data)
+	}
+
+	return snapshot(o, chart, title)
+}
+
+func renderCacheChart(o *Options, frameworks []namedFramework, title string, fn func(benchflix.Cache) opts.BarData) error {
+	chart := newBar(title, frameworks)
+
+	for _, c := range []struct {
+		Name string
+		Get  func(benchflix.Framework) benchflix.Cache
+	}{
+		{"ListPreloadCached", func(f benchflix.Framework) benchflix.Cache { return f.ListPreloadCached }},
+		{"DashboardPreloadCached", func(f benchflix.Framework) benchflix.Cache { return f.DashboardPreloadCached }},
+	} {
+		var data []opts.BarData
+
+		for _, f := range frameworks {
+			data = append(data, fn(c.Get(f.Framework)))
+		}
+
+		chart.AddSeries(c.Name, data)
+	}
+
+	return snapshot(o, chart, title)
+}
+
+func newBar(title string, frameworks []namedFramework) *charts.Bar {
+	chart := charts.NewBar()
+	chart.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: title,
+		}),
+		charts.WithAnimation(false),
+		charts.WithInitializationOpts(opts.Initialization{
+			BackgroundColor: "#FFFFFF",
+		}),
+	)
+
+	var names []string
+
+	for _, f := range frameworks {
+		names = append(names, f.Name)
+	}
+
+	chart.SetXAxis(names)
+
+	return chart
+}
+
+func snapshot(o *Options, chart *charts.Bar, title string) error {
+	if err := os.MkdirAll(o.Data, 0o755); err != nil {
+		return err
+	}
+
+	return render.MakeChartSnapshot(chart.RenderContent(), filepath.Join(o.Data, strings.ReplaceAll(title, " ", "_")+".png"))
+}
+
+func ignoreErr(q stats.Quartiles, err error) stats.Quartiles {
+	return q
+}
@@ -0,0 +1,246 @@
+// Package cli implements the benchflix command and its subcommands.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-sqlt/benchflix"
+)
+
+// Exit codes of Main.
+const (
+	ExitOK         = 0
+	ExitError      = 1
+	ExitUsage      = 2
+	ExitRegression = 3
+)
+
+// ErrUsage is returned for invalid flags and arguments.
+var ErrUsage = errors.New("usage")
+
+// ErrRegression is returned by compare if a result got slower than allowed.
+var ErrRegression = errors.New("regression")
+
+type command struct {
+	Name    string
+	Summary string
+	Run     func(o *Options, args []string) error
+}
+
+var commands = []command{
+	{"params", "generate random params", params},
+	{"load", "start a postgres container loaded with the movies", load},
+	{"run", "run szenarios without go test", run},
+	{"charts", "render charts of the results", renderCharts},
+	{"tables", "write latex tables of the results", tables},
+	{"maintainability", "write latex tables of code metrics read from stdin", maintainability},
+	{"compare", "compare the results against a baseline", compare},
+	{"semantic", "turn a prompt into dashboard params and run them", semantic},
+}
+
+// Options are the flags shared by all subcommands.
+type Options struct {
+	Data       string
+	Results    string
+	Frameworks []string
+	Labels     map[string]string
+
+	Stdin          io.Reader
+	Stdout, Stderr io.Writer
+}
+
+// Main runs the subcommand named by args[0] and returns the exit code.
+func Main(args []string) int {
+	o := &Options{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		o.usage()
+
+		if len(args) == 0 {
+			return ExitUsage
+		}
+
+		return ExitOK
+	}
+
+	index := slices.IndexFunc(commands, func(c command) bool { return c.Name == args[0] })
+	if index < 0 {
+		fmt.Fprintf(o.Stderr, "benchflix: unknown command %q\n", args[0])
+		o.usage()
+
+		return ExitUsage
+	}
+
+	cmd := commands[index]
+
+	err := cmd.Run(o, args[1:])
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.Is(err, ErrUsage):
+		fmt.Fprintf(o.Stderr, "benchflix %s: %v\n", cmd.Name, err)
+
+		return ExitUsage
+	case errors.Is(err, ErrRegression):
+		fmt.Fprintf(o.Stderr, "benchflix %s: %v\n", cmd.Name, err)
+
+		return ExitRegression
+	default:
+		fmt.Fprintf(o.Stderr, "benchflix %s: %v\n", cmd.Name, err)
+
+		return ExitError
+	}
+}
+
+func (o *Options) usage() {
+	fmt.Fprint(o.Stderr, "usage: benchflix <command> [flags]\n\ncommands:\n")
+
+	for _, c := range commands {
+		fmt.Fprintf(o.Stderr, "  %-16s %s\n", c.Name, c.Summary)
+	}
+
+	fmt.Fprintf(o.Stderr, "\nexit codes: %d ok, %d error, %d usage, %d regression\n", ExitOK, ExitError, ExitUsage, ExitRegression)
+}
+
+// FlagSet returns a flag set with the shared flags. Parse it with o.Parse.
+func (o *Options) FlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("benchflix "+name, flag.ContinueOnError)
+
+	fs.SetOutput(o.Stderr)
+	fs.StringVar(&o.Data, "data", "data", "directory for charts and tables")
+	fs.StringVar(&o.Results, "results", "-", "results file, - for stdin or stdout")
+	fs.Func("frameworks", "comma separated frameworks, all if empty", func(s string) error {
+		o.Frameworks = strings.Split(s, ",")
+
+		return nil
+	})
+	fs.Func("label", "only results with this label, e.g. pool=3-6, repeatable", func(s string) error {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("invalid label %q, want key=value", s)
+		}
+
+		if o.Labels == nil {
+			o.Labels = map[string]string{}
+		}
+
+		o.Labels[key] = value
+
+		return nil
+	})
+
+	return fs
+}
+
+// Parse parses args and rejects positional arguments.
+func (o *Options) Parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %w", ErrUsage, err)
+	}
+
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected arguments %q", ErrUsage, fs.Args())
+	}
+
+	return nil
+}
+
+// Include reports whether the framework filter selects name.
+func (o *Options) Include(name string) bool {
+	return len(o.Frameworks) == 0 || slices.Contains(o.Frameworks, name)
+}
+
+// ReadResults reads the go test output in o.Results.
+func (o *Options) ReadResults() (benchflix.Benchmark, error) {
+	return o.readResults(o.Results)
+}
+
+func (o *Options) readResults(path string) (benchflix.Benchmark, error) {
+	r, err := o.Open(path)
+	if err != nil {
+		return benchflix.Benchmark{}, err
+	}
+
+	defer r.Close()
+
+	b, err := benchflix.ReadAllWhere(r, o.Labels)
+	if err != nil {
+		return b, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return b, nil
+}
+
+// Open opens path for reading, - is stdin.
+func (o *Options) Open(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(o.Stdin), nil
+	}
+
+	return os.Open(path)
+}
+
+// Create creates path for writing, - is stdout.
+func (o *Options) Create(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopWriteCloser{o.Stdout}, nil
+	}
+
+	return os.Create(path)
+}
+
+// CreateData creates name in the data directory.
+func (o *Options) CreateData(name string) (*os.File, error) {
+	if err := os.MkdirAll(o.Data, 0o755); err != nil {
+		return nil, err
+	}
+
+	return os.Create(filepath.Join(o.Data, name))
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+type namedFramework struct {
+	Name      string
+	Framework benchflix.Framework
+}
+
+// frameworks returns the frameworks of b selected by the filter.
+func (o *Options) frameworks(b benchflix.Benchmark) []namedFramework {
+	var selected []namedFramework
+
+	for _, f := range []namedFramework{
+		{"SQL", b.SQL},
+		{"PGX", b.PGX},
+		{"SQUIRREL", b.SQUIRREL},
+		{"SQLX", b.SQLX},
+		{"GORM", b.GORM},
+		{"SQLC", b.SQLC},
+		{"SQLT", b.SQLT},
+		{"SQLT-Cache", b.SQLTCACHE},
+	} {
+		if o.Include(f.Name) {
+			selected = append(selected, f)
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/go-sqlt/benchflix"
+	"github.com/montanaflynn/stats"
+)
+
+// compare prints the change of the medians between the baseline and the
+// results and fails with ErrRegression if any grew by more than the threshold.
+func compare(o *Options, args []string) error {
+	fs := o.FlagSet("compare")
+	basePath := fs.String("base", "", "baseline results file")
+	threshold := fs.Float64("threshold", 0.05, "relative growth of a median that counts as regression")
+
+	if err := o.Parse(fs, args); err != nil {
+		return err
+	}
+
+	if *basePath == "" {
+		return fmt.Errorf("%w: -base is required", ErrUsage)
+	}
+
+	base, err := o.readResults(*basePath)
+	if err != nil {
+		return err
+	}
+
+	results, err := o.ReadResults()
+	if err != nil {
+		return err
+	}
+
+	baseFrameworks := o.frameworks(base)
+
+	w := tabwriter.NewWriter(o.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(w, "framework\tbenchmark\tmetric\tbase\tnew\tdelta\t")
+
+	var regressions int
+
+	for i, f := range o.frameworks(results) {
+		old := map[string]benchflix.Params{}
+
+		eachParams(baseFrameworks[i].Framework, func(name string, p benchflix.Params) { old[name] = p })
+
+		eachParams(f.Framework, func(name string, p benchflix.Params) {
+			for _, metric := range metrics {
+				before, errBefore := stats.Median(metric.Values(old[name]))
+				after, errAfter := stats.Median(metric.Values(p))
+
+				if errBefore != nil || errAfter != nil || before == 0 {
+					continue
+				}
+
+				delta := after/before - 1
+
+				mark := ""
+				if delta > *threshold {
+					mark = " !"
+					regressions++
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%.0f\t%.0f\t%+.1f%%%s\t\n", f.Name, name, metric.Name, before, after, delta*100, mark)
+			}
+		})
+	}
+
+	if err = w.Flush(); err != nil {
+		return err
+	}
+
+	if regressions > 0 {
+		return fmt.Errorf("%w: %d medians grew by more than %.1f%%", ErrRegression, regressions, *threshold*100)
+	}
+
+	return nil
+}
+
+// eachParams calls fn with every params of f, named like the sub-benchmark.
+func eachParams(f benchflix.Framework, fn func(name string, p benchflix.Params)) {
+	for _, s := range szenarios {
+		fn(s.Name+"/100", s.Get(f).Hundred)
+		fn(s.Name+"/1000", s.Get(f).Thousand)
+	}
+
+	for _, s := range []struct {
+		Name     string
+		Strategy benchflix.Strategy
+	}{
+		{"PreloadAny", f.PreloadAny},
+		{"PreloadUnnest", f.PreloadUnnest},
+		{"PreloadTempTable", f.PreloadTempTable},
+		{"PreloadBatch", f.PreloadBatch},
+	} {
+		fn(s.Name+"/10", s.Strategy.Ten)
+		fn(s.Name+"/1000", s.Strategy.Thousand)
+		fn(s.Name+"/10000", s.Strategy.TenThousand)
+		fn(s.Name+"/50000", s.Strategy.FiftyThousand)
+	}
+
+	for _, c := range []struct {
+		Name  string
+		Cache benchflix.Cache
+	}{
+		{"ListPreloadCached", f.ListPreloadCached},
+		{"DashboardPreloadCached", f.DashboardPreloadCached},
+	} {
+		fn(c.Name+"/10", c.Cache.Ten)
+		fn(c.Name+"/100", c.Cache.Hundred)
+		fn(c.Name+"/1000", c.Cache.Thousand)
+	}
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/go-sqlt/benchflix"
+)
+
+// load starts a postgres container with the movies, prints its connection
+// string and keeps it running until interrupted, so that run and semantic can
+// share it via -conn.
+func load(o *Options, args []string) error {
+	fs := o.FlagSet("load")
+	name := fs.String("name", "benchflix", "container name, an existing container of that name is replaced")
+
+	if err := o.Parse(fs, args); err != nil {
+		return err
+	}
+
+	conn, resource, err := benchflix.StartPostgres(*name)
+	if err != nil {
+		return err
+	}
+
+	defer resource.Close()
+
+	if _, err := fmt.Fprintln(o.Stdout, conn); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	defer stop()
+
+	<-ctx.Done()
+
+	return nil
+}
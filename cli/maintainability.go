@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type maintainabilityData struct {
+	Function string
+	CC       int
+	HV       float64
+	MI       int
+}
+
+func maintainability(o *Options, args []string) error {
+	fs := o.FlagSet("maintainability")
+	in := fs.String("in", "-", "output of the code metrics tool, - for stdin")
+
+	if err := o.Parse(fs, args); err != nil {
+		return err
+	}
+
+	r, err := o.Open(*in)
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	scan := bufio.NewScanner(r)
+
+	var sql, pgx, squirrel, sqlx, gorm, sqlc, sqlt []maintainabilityData
+
+	for scan.Scan() {
+		text := scan.Text()
+
+		fnStart := strings.Index(text, "Function name:")
+		if fnStart < 0 {
+			continue
+		}
+
+		fnEnd := strings.Index(text, ", Cyclomatic Complexity:")
+
+		var funcName string
+
+		switch strings.TrimSpace(text[fnStart+len("Function name:") : fnEnd]) {
+		// the setup lives in Open, NewRepository only wraps it
+		case "Open":
+			funcName = "NewRepository"
+		case "QueryList":
+			funcName = "List"
+		case "QueryListPreload":
+			funcName = "ListPreload"
+		case "QueryListNPlusOne":
+			funcName = "ListNPlusOne"
+		case "QueryListJSON":
+			funcName = "ListJSON"
+		case "QueryDashboard":
+			funcName = "Dashboard"
+		case "QueryDashboardPreload":
+			funcName = "DashboardPreload"
+		case "QueryDetails":
+			funcName = "Details"
+		case "QueryDetailsNull":
+			funcName = "DetailsNull"
+		case "QuerySearch":
+			funcName = "Search"
+		case "QueryFacets":
+			funcName = "Facets"
+		case "QueryFacetsBatch":
+			funcName = "FacetsBatch"
+		case "QueryTopRated":
+			funcName = "TopRated"
+		case "QueryCollaborators":
+			funcName = "Collaborators"
+		case "QueryMovie":
+			funcName = "Movie"
+		case "QueryWide":
+			funcName = "Wide"
+		case "QueryDirectors":
+			funcName = "Preload"
+		}
+
+		if funcName == "" {
+			continue
+		}
+
+		ccStart := strings.Index(text, "Cyclomatic Complexity:") + len("Cyclomatic Complexity:")
+		ccEnd := strings.Index(text, ", Halstead Volume:")
+		ccStr := strings.TrimSpace(text[ccStart:ccEnd])
+
+		hvStart := strings.Index(text, "Halstead Volume:") + len("Halstead Volume:")
+		hvEnd := strings.Index(text, ", Maintainability Index:")
+		hvStr := strings.TrimSpace(text[hvStart:hvEnd])
+
+		miStart := strings.Index(text, "Maintainability Index:") + len("Maintainability Index:")
+		miStr := strings.TrimSpace(text[miStart:])
+
+		cc, err1 := strconv.Atoi(ccStr)
+		hv, err2 := strconv.ParseFloat(hvStr, 64)
+		mi, err3 := strconv.Atoi(miStr)
+		if err1 != nil || err2 != nil || err3 != nil {
+			fmt.Fprintf(o.Stderr, "benchflix maintainability: Fehler beim Parsen: %v %v %v\n", err1, err2, err3)
+			continue
+		}
+
+		if cc == 1 && hv < 100 {
+			continue
+		}
+
+		data := maintainabilityData{
+			Function: funcName,
+			CC:       cc,
+			HV:       hv,
+			MI:       mi,
+		}
+
+		switch {
+		case strings.Contains(text, "sqlflix"):
+			sql = append(sql, data)
+		case strings.Contains(text, "pgxflix"):
+			pgx = append(pgx, data)
+		case strings.Contains(text, "squirrelflix"):
+			squirrel = append(squirrel, data)
+		case strings.Contains(text, "sqlxflix"):
+			sqlx = append(sqlx, data)
+		case strings.Contains(text, "gormflix"):
+			gorm = append(gorm, data)
+		case strings.Contains(text, "sqlcflix"):
+			sqlc = append(sqlc, data)
+		case strings.Contains(text, "sqltflix"):
+			sqlt = append(sqlt, data)
+		}
+	}
+
+	if err = scan.Err(); err != nil {
+		return err
+	}
+
+	for _, f := range []struct {
+		Name string
+		Data []maintainabilityData
+	}{
+		{"SQL", sql},
+		{"PGX", pgx},
+		{"SQUIRREL", squirrel},
+		{"SQLX", sqlx},
+		{"GORM", gorm},
+		{"SQLC", sqlc},
+		{"SQLT", sqlt},
+	} {
+		if !o.Include(f.Name) {
+			continue
+		}
+
+		if err = maintainabilityTable(o, f.Name, f.Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func maintainabilityTable(o *Options, name string, data []maintainabilityData) error {
+	file, err := o.CreateData(fmt.Sprintf("%s_maintainability.tex", strings.ToLower(name)))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(file, `\begin{table}[ht]
+\centering
+\caption{%s: Wartbarkeit}
+\begin{tabular}{lrrrr}
+\toprule
+Szenario & CC & HV & MI \\
+\midrule`, name)
+
+	for _, d := range data {
+		fmt.Fprintf(file, `
+	%s & %d & %g & %d \\`, d.Function, d.CC, math.Round(d.HV), d.MI,
+		)
+	}
+
+	fmt.Fprintf(file, `
+\bottomrule
+\end{tabular}
+\label{tab:%s_maintainability}
+\end{table}
+	`, strings.ToLower(name))
+
+	return file.Close()
+}
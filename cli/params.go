@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/go-sqlt/benchflix"
+)
+
+func params(o *Options, args []string) error {
+	fs := o.FlagSet("params")
+	size := fs.Int("size", 1000, "number of params")
+	path := fs.String("params", "params.json", "params file to write, - for stdout")
+
+	if err := o.Parse(fs, args); err != nil {
+		return err
+	}
+
+	if *size <= 0 {
+		return fmt.Errorf("%w: size must be positive", ErrUsage)
+	}
+
+	params := make([]benchflix.DashboardParams, *size)
+
+	for i := range *size {
+		params[i] = randomDashboardParams()
+	}
+
+	w, err := o.Create(*path)
+	if err != nil {
+		return err
+	}
+
+	if err = json.NewEncoder(w).Encode(params); err != nil {
+		_ = w.Close()
+
+		return err
+	}
+
+	return w.Close()
+}
+
+var (
+	search        = []string{"", "the", "to", "of", "a", "little", "shark", "thing"}
+	sort          = []string{"title", "added_at", "rating"}
+	desc          = []bool{true, false}
+	withDirectors = []bool{true, false}
+)
+
+func randomDashboardParams() benchflix.DashboardParams {
+	return benchflix.DashboardParams{
+		Search:        search[rand.IntN(len(search))],
+		YearAdded:     2000 + rand.Int64N(25),
+		MinRating:     float64(rand.IntN(100)) / 10,
+		Limit:         1 + rand.Uint64N(99),
+		Sort:          sort[rand.IntN(len(sort))],
+		Desc:          desc[rand.IntN(len(desc))],
+		WithDirectors: withDirectors[rand.IntN(len(withDirectors))],
+	}
+}
@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	_ "net/http/pprof"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sqlt/benchflix"
+	_ "github.com/go-sqlt/benchflix/gormflix"
+	_ "github.com/go-sqlt/benchflix/pgxflix"
+	_ "github.com/go-sqlt/benchflix/sqlcflix"
+	_ "github.com/go-sqlt/benchflix/sqlflix"
+	_ "github.com/go-sqlt/benchflix/sqltflix"
+	_ "github.com/go-sqlt/benchflix/sqlxflix"
+	_ "github.com/go-sqlt/benchflix/squirrelflix"
+)
+
+type runner func(ctx context.Context, r benchflix.Runner, repo benchflix.Repository, params benchflix.ParamSet, size int) (benchflix.RunResult, error)
+
+func runnerOf[P, R any](query func(benchflix.Repository, context.Context, P) (R, error), params func(benchflix.ParamSet) []P) runner {
+	return func(ctx context.Context, r benchflix.Runner, repo benchflix.Repository, p benchflix.ParamSet, size int) (benchflix.RunResult, error) {
+		all := params(p)
+
+		return benchflix.Run(ctx, r, repo, query, all[:min(size, len(all))])
+	}
+}
+
+var runners = map[string]runner{
+	"List":             runnerOf(benchflix.Repository.QueryList, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	"ListPreload":      runnerOf(benchflix.Repository.QueryListPreload, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	"ListNPlusOne":     runnerOf(benchflix.Repository.QueryListNPlusOne, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	"ListJSON":         runnerOf(benchflix.Repository.QueryListJSON, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	"Details":          runnerOf(benchflix.Repository.QueryDetails, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	"DetailsNull":      runnerOf(benchflix.Repository.QueryDetailsNull, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	"Dashboard":        runnerOf(benchflix.Repository.QueryDashboard, func(p benchflix.ParamSet) []benchflix.DashboardParams { return p.Dashboard }),
+	"DashboardPreload": runnerOf(benchflix.Repository.QueryDashboardPreload, func(p benchflix.ParamSet) []benchflix.DashboardParams { return p.Dashboard }),
+	"Facets":           runnerOf(benchflix.Repository.QueryFacets, func(p benchflix.ParamSet) []benchflix.DashboardParams { return p.Dashboard }),
+	"FacetsBatch":      runnerOf(benchflix.Repository.QueryFacetsBatch, func(p benchflix.ParamSet) []benchflix.DashboardParams { return p.Dashboard }),
+	"Search":           runnerOf(benchflix.Repository.QuerySearch, func(p benchflix.ParamSet) []benchflix.SearchParams { return p.Search }),
+	"Websearch":        runnerOf(benchflix.Repository.QuerySearch, func(p benchflix.ParamSet) []benchflix.SearchParams { return p.Websearch }),
+	"TopRated":         runnerOf(benchflix.Repository.QueryTopRated, func(p benchflix.ParamSet) []benchflix.TopParams { return p.Top }),
+	"Collaborators":    runnerOf(benchflix.Repository.QueryCollaborators, func(p benchflix.ParamSet) []benchflix.GraphParams { return p.Graph }),
+	"Movie":            runnerOf(benchflix.Repository.QueryMovie, func(p benchflix.ParamSet) []int64 { return p.MovieIDs }),
+	"Wide":             runnerOf(benchflix.Repository.QueryWide, func(p benchflix.ParamSet) []benchflix.WideParams { return p.Wide }),
+}
+
+func run(o *Options, args []string) error {
+	fs := o.FlagSet("run")
+	name := fs.String("szenario", "List", "szenario: "+strings.Join(keys(runners), ", "))
+	path := fs.String("params", "params.json", "params file generated by benchflix params")
+	size := fs.Int("size", 1000, "number of params")
+	duration := fs.Duration("duration", 10*time.Second, "measured duration per framework, 0 runs until interrupted")
+	concurrency := fs.Int("concurrency", 6, "concurrent calls, also the maximum number of connections")
+	warmup := fs.String("warmup", "count:12500", "warmup policy: count:N, duration:D or steady:WINDOW:TOLERANCE:MAXCALLS")
+	interval := fs.Duration("interval", 0, "write intermediate results every interval")
+	conn := fs.String("conn", "", "connection string of a loaded database, e.g. from benchflix load, starts a docker container if empty")
+	pprof := fs.String("pprof", "", "serve net/http/pprof on this address, e.g. localhost:6060")
+	metricsAddr := fs.String("metrics", "", "serve prometheus metrics of the running framework under /metrics on this address, e.g. localhost:9090")
+	traces := fs.String("traces", "", "write an OTLP/JSON span per call to this file")
+
+	if err := o.Parse(fs, args); err != nil {
+		return err
+	}
+
+	var names []string
+
+	for _, f := range benchflix.DefaultFrameworks {
+		names = append(names, f.Name)
+	}
+
+	for _, f := range o.Frameworks {
+		if !slices.Contains(names, f) {
+			return fmt.Errorf("%w: invalid framework %q, want one of %s", ErrUsage, f, strings.Join(names, ", "))
+		}
+	}
+
+	szenario, ok := runners[*name]
+	if !ok {
+		return fmt.Errorf("%w: invalid szenario %q, want one of %s", ErrUsage, *name, strings.Join(keys(runners), ", "))
+	}
+
+	policy, err := benchflix.ParseWarmup(*warmup)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUsage, err)
+	}
+
+	policy.Settle = 500 * time.Millisecond
+
+	params, err := benchflix.LoadParamSet(*path)
+	if err != nil {
+		return err
+	}
+
+	if *pprof != "" {
+		listener, err := net.Listen("tcp", *pprof)
+		if err != nil {
+			return err
+		}
+
+		go func() { _ = http.Serve(listener, nil) }()
+	}
+
+	var metrics atomic.Pointer[benchflix.Metrics]
+
+	if *metricsAddr != "" {
+		listener, err := net.Listen("tcp", *metricsAddr)
+		if err != nil {
+			return err
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			if m := metrics.Load(); m != nil {
+				m.ServeHTTP(w, r)
+			}
+		})
+
+		go func() { _ = http.Serve(listener, mux) }()
+	}
+
+	var spans *bufio.Writer
+
+	if *traces != "" {
+		file, err := os.Create(*traces)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		spans = bufio.NewWriter(file)
+	}
+
+	w, err := o.Create(o.Results)
+	if err != nil {
+		return err
+	}
+
+	defer w.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+
+	defer stop()
+
+	enc := json.NewEncoder(w)
+
+	for _, framework := range benchflix.DefaultFrameworks {
+		if !o.Include(framework.Name) || ctx.Err() != nil {
+			continue
+		}
+
+		var middlewares []benchflix.Middleware
+
+		if *metricsAddr != "" {
+			m := benchflix.NewMetrics()
+
+			metrics.Store(m)
+
+			middlewares = append(middlewares, m.Middleware)
+		}
+
+		if spans != nil {
+			middlewares = append(middlewares, benchflix.NewTracer(spans, framework.Name).Middleware)
+		}
+
+		if err = runFramework(ctx, o, framework, *conn, *name, benchflix.Runner{
+			Concurrency: *concurrency,
+			Duration:    *duration,
+			Warmup:      policy,
+			Interval:    *interval,
+		}, szenario, params, *size, middlewares, enc); err != nil {
+			return err
+		}
+	}
+
+	if spans != nil {
+		if err = spans.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return w.Close()
+}
+
+// runFramework runs the szenario against one framework and writes every
+// result to enc. It starts a container unless conn is given and wraps the
+// repository with the middlewares.
+func runFramework(ctx context.Context, o *Options, framework benchflix.MatrixFramework, conn, name string, r benchflix.Runner, szenario runner, params benchflix.ParamSet, size int, middlewares []benchflix.Middleware, enc *json.Encoder) error {
+	if conn == "" {
+		c, resource, err := benchflix.StartPostgres(framework.Name)
+		if err != nil {
+			return err
+		}
+
+		defer resource.Close()
+
+		conn = c
+	}
+
+	repo, err := framework.Open(conn, r.Concurrency/2, r.Concurrency, 2*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	repo = benchflix.Chain(repo, middlewares...)
+
+	if c, ok := repo.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	var mu sync.Mutex
+
+	r.Report = func(result benchflix.RunResult) {
+		result.Framework, result.Szenario = framework.Name, name
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		_ = enc.Encode(result)
+	}
+
+	result, err := szenario(ctx, r, repo, params, size)
+
+	if errors.Is(err, benchflix.ErrSkip) {
+		fmt.Fprintf(o.Stderr, "benchflix run: %s does not support %s\n", framework.Name, name)
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", framework.Name, name, err)
+	}
+
+	r.Report(result)
+
+	return nil
+}
+
+func keys[V any](m map[string]V) []string {
+	k := make([]string, 0, len(m))
+
+	for key := range m {
+		k = append(k, key)
+	}
+
+	slices.Sort(k)
+
+	return k
+}
@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/go-sqlt/benchflix"
+	"github.com/go-sqlt/benchflix/sqltflix"
+	"github.com/go-sqlt/sqlt"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const openAIURL = "https://api.openai.com/v1/chat/completions"
+
+func semantic(o *Options, args []string) error {
+	fs := o.FlagSet("semantic")
+	in := fs.String("in", "-", "prompt, - for stdin")
+	conn := fs.String("conn", "", "connection string of a loaded database, e.g. from benchflix load, starts a docker container if empty")
+
+	if err := o.Parse(fs, args); err != nil {
+		return err
+	}
+
+	if os.Getenv("OPENAI_API_KEY") == "" {
+		return fmt.Errorf("%w: OPENAI_API_KEY is not set", ErrUsage)
+	}
+
+	r, err := o.Open(*in)
+	if err != nil {
+		return err
+	}
+
+	prompt, err := io.ReadAll(r)
+
+	_ = r.Close()
+
+	if err != nil {
+		return err
+	}
+
+	params, err := send[benchflix.DashboardParams](o, string(prompt))
+	if err != nil {
+		return err
+	}
+
+	if *conn == "" {
+		c, resource, err := benchflix.StartPostgres("Semantic")
+		if err != nil {
+			return err
+		}
+
+		defer resource.Close()
+
+		*conn = c
+	}
+
+	repo, err := sqltflix.Open(*conn, 3, 6, 2*time.Second, sqlt.Config{})
+	if err != nil {
+		return err
+	}
+
+	defer repo.Close()
+
+	movies, err := repo.QueryDashboard(context.Background(), params)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(o.Stdout, movies)
+
+	return err
+}
+
+func printStruct[T any]() (string, error) {
+	t := reflect.TypeFor[T]()
+
+	code := fmt.Sprintf("type %s struct {\n", t.Name())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		code += fmt.Sprintf("\t%s %s `%s`\n", field.Name, field.Type, field.Tag)
+	}
+	code += "}\n"
+
+	source, err := format.Source([]byte(code))
+
+	return string(source), err
+}
+
+func send[T any](o *Options, prompt string) (T, error) {
+	var t T
+
+	definition, err := printStruct[T]()
+	if err != nil {
+		return t, err
+	}
+
+	reqBody := map[string]any{
+		"model": "gpt-4.1-mini",
+		"messages": []map[string]string{
+			{
+				"role": "system",
+				"content": `You convert natural language into a JSON object that matches the following Go struct (all field names are in snake_case).
+				` + definition + `
+				Return only valid JSON, no code blocks. Pay attention to the json struct tags.
+				This is the query generated based on the input of ListParams:
+				SELECT
+					m.id                    {{ Scan.Int.To "ID" }}
+					, m.title               {{ Scan.String.To "Title" }}
+					, m.added_at            {{ Scan.Time.To "AddedAt" }}
+					, m.rating              {{ Scan.Float.To "Rating" }}
+					{{ if .WithDirectors }}
+						, d.directors       {{ Scan.StringSlice.To "Directors" }}
+					{{ end }}
+				FROM movies m
+				{{ if .WithDirectors }}
+					LEFT JOIN LATERAL (
+						SELECT ARRAY_AGG(p.name ORDER BY p.name) AS directors
+						FROM movie_directors md
+						JOIN people p ON p.id = md.person_id
+						WHERE md.movie_id = m.id
+					) d ON true
+				{{ end }}
+				WHERE 1=1
+				{{ if .Search }}
+					AND (
+						to_tsvector('simple', m.title) @@ plainto_tsquery('simple', {{ .Search }})
+						OR EXISTS (
+							SELECT 1
+							FROM movie_directors md
+							JOIN people p ON p.id = md.person_id
+							WHERE md.movie_id = m.id
+							AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', {{ .Search }})
+						)
+					)
+				{{ end }}
+				{{ if .YearAdded }} AND EXTRACT(YEAR FROM m.added_at) = {{ .YearAdded }}{{ end }}
+				{{ if .MinRating }} AND m.rating >= {{ .MinRating }} {{ end }}
+				ORDER BY
+				{{ if eq .Sort "title" }} m.title
+					{{ else if eq .Sort "added_at" }} m.added_at
+					{{ else }} m.rating
+				{{ end }} 
+				{{ if .Desc }} DESC{{ else }} ASC{{ end }}
+				{{ if and (gt .Limit 0) (lt .Limit 1000) }} LIMIT {{ .Limit }}{{ else }} LIMIT 1000{{ end }}
+				`,
+			},
+			{
+				"role":    "user",
+				"content": string(prompt),
+			},
+		},
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return t, err
+	}
+
+	req, err := http.NewRequest("POST", openAIURL, bytes.NewBuffer(body))
+	if err != nil {
+		return t, err
+	}
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("OPENAI_API_KEY"))
+	req.Header.Set("Content-Type", "application/json")
+
+	client := http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return t, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(resp.Body)
+
+		return t, fmt.Errorf("openai: %s: %s", resp.Status, message)
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return t, err
+	}
+
+	if len(result.Choices) == 0 {
+		return t, errors.New("openai: no choices")
+	}
+
+	fmt.Fprintln(o.Stdout, result.Choices[0].Message.Content)
+
+	if err := json.Unmarshal([]byte(result.Choices[0].Message.Content), &t); err != nil {
+		return t, fmt.Errorf("openai: %w", err)
+	}
+
+	return t, nil
+}
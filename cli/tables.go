@@ -0,0 +1,764 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/go-sqlt/benchflix"
+	"github.com/montanaflynn/stats"
+)
+
+func tables(o *Options, args []string) error {
+	if err := o.Parse(o.FlagSet("tables"), args); err != nil {
+		return err
+	}
+
+	b, err := o.ReadResults()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range o.frameworks(b) {
+		// every framework is compared against SQL
+		base := b.SQL
+		if f.Name == "SQL" {
+			base = benchflix.Framework{}
+		}
+
+		if err = errors.Join(
+			nsPerOpTable(o, f.Name, f.Framework, base),
+			bytesPerOpTable(o, f.Name, f.Framework, base),
+			allocsPerOpTable(o, f.Name, f.Framework, base),
+			poolTable(o, f.Name, f.Framework),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var szenarios = []struct {
+	Name string
+	Get  func(benchflix.Framework) benchflix.Szenario
+}{
+	{"List", func(f benchflix.Framework) benchflix.Szenario { return f.List }},
+	{"ListPreload", func(f benchflix.Framework) benchflix.Szenario { return f.ListPreload }},
+	{"ListPreloadLoader", func(f benchflix.Framework) benchflix.Szenario { return f.ListPreloadLoader }},
+	{"ListNPlusOne", func(f benchflix.Framework) benchflix.Szenario { return f.ListNPlusOne }},
+	{"ListJSON", func(f benchflix.Framework) benchflix.Szenario { return f.ListJSON }},
+	{"Dashboard", func(f benchflix.Framework) benchflix.Szenario { return f.Dashboard }},
+	{"DashboardPreload", func(f benchflix.Framework) benchflix.Szenario { return f.DashboardPreload }},
+	{"DashboardPreloadLoader", func(f benchflix.Framework) benchflix.Szenario { return f.DashboardPreloadLoader }},
+	{"Details", func(f benchflix.Framework) benchflix.Szenario { return f.Details }},
+	{"DetailsNull", func(f benchflix.Framework) benchflix.Szenario { return f.DetailsNull }},
+	{"Search", func(f benchflix.Framework) benchflix.Szenario { return f.Search }},
+	{"Websearch", func(f benchflix.Framework) benchflix.Szenario { return f.Websearch }},
+	{"Facets", func(f benchflix.Framework) benchflix.Szenario { return f.Facets }},
+	{"FacetsBatch", func(f benchflix.Framework) benchflix.Szenario { return f.FacetsBatch }},
+	{"TopRated", func(f benchflix.Framework) benchflix.Szenario { return f.TopRated }},
+	{"Collaborators", func(f benchflix.Framework) benchflix.Szenario { return f.Collaborators }},
+	{"Movie", func(f benchflix.Framework) benchflix.Szenario { return f.Movie }},
+	{"Wide", func(f benchflix.Framework) benchflix.Szenario { return f.Wide }},
+}
+
+// poolTable writes the medians of the pool counters sampled during each
+// benchmark. database/sql does not count acquires, its cells stay empty.
+func poolTable(o *Options, name string, framework benchflix.Framework) error {
+	file, err := o.CreateData(fmt.Sprintf("%s_pool.tex", strings.ToLower(name)))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(file, `
+\begin{table}[ht]
+\centering
+\caption{%s: Verbindungspool}
+\begin{tabular}{lrrrrrrrr}
+\toprule
+Szenario & Params & Acquires/Op & Leer/Op & ns/Acquire & Wartend/Op & Wartezeit ns/Op & Neu & Geschlossen \\
+\midrule
+`, name)
+
+	eachParams(framework, func(benchmark string, p benchflix.Params) {
+		if len(p.NewConns) == 0 {
+			return
+		}
+
+		szenario, size, _ := strings.Cut(benchmark, "/")
+
+		fmt.Fprintf(file, `
+	%s & %s & %s & %s & %s & %s & %s & %g & %g \\`,
+			szenario, size, poolCell(p.AcquiresPerOp, "%.2f"), poolCell(p.EmptyAcquiresPerOp, "%.2f"), poolCell(p.NsPerAcquire, "%.0f"),
+			poolCell(p.WaitsPerOp, "%.2f"), poolCell(p.WaitNsPerOp, "%.0f"),
+			median(p.NewConns), median(p.ClosedConns))
+	})
+
+	fmt.Fprintf(file, `
+\bottomrule
+\end{tabular}
+\label{tab:benchmark_%s_pool}
+\end{table}
+	`, strings.ToLower(name))
+
+	return file.Close()
+}
+
+// poolCell formats the median of values, or nothing if there are none.
+func poolCell(values []float64, format string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(format, median(values))
+}
+
+func median(values []float64) float64 {
+	m, _ := stats.Median(values)
+
+	return m
+}
+
+func nsPerOpTable(o *Options, name string, framework benchflix.Framework, base benchflix.Framework) error {
+	file, err := o.CreateData(fmt.Sprintf("%s_nsperop.tex", strings.ToLower(name)))
+	if err != nil {
+		return err
+	}
+
+	delta := fmt.Sprintf(`& ${\Delta M_{%s,SQL}}$`, name)
+	if reflect.DeepEqual(base, benchflix.Framework{}) {
+		delta = ""
+	}
+
+	fmt.Fprintf(file, `
+\begin{table}[ht]
+\centering
+\caption{%s: Nanosekunden pro Operation}
+\begin{tabular}{lrrrr}
+\toprule
+Szenario & Params & ${M_{%s}}$ & ${QA_{%s}}$ %s  \\
+\midrule
+`, name, name, name, delta)
+
+	printRow(file, "List", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.List.Hundred.NsPerOp)
+	})
+	printRow(file, "ListPreload", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreload.Hundred.NsPerOp)
+	})
+	printRow(file, "ListPreloadLoader", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadLoader.Hundred.NsPerOp)
+	})
+	printRow(file, "ListNPlusOne", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListNPlusOne.Hundred.NsPerOp)
+	})
+	printRow(file, "ListJSON", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListJSON.Hundred.NsPerOp)
+	})
+	printRow(file, "Dashboard", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Dashboard.Hundred.NsPerOp)
+	})
+	printRow(file, "DashboardPreload", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreload.Hundred.NsPerOp)
+	})
+	printRow(file, "DashboardPreloadLoader", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadLoader.Hundred.NsPerOp)
+	})
+	printRow(file, "Details", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Details.Hundred.NsPerOp)
+	})
+	printRow(file, "DetailsNull", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DetailsNull.Hundred.NsPerOp)
+	})
+	printRow(file, "Search", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Search.Hundred.NsPerOp)
+	})
+	printRow(file, "Websearch", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Websearch.Hundred.NsPerOp)
+	})
+	printRow(file, "Facets", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Facets.Hundred.NsPerOp)
+	})
+	printRow(file, "FacetsBatch", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.FacetsBatch.Hundred.NsPerOp)
+	})
+	printRow(file, "TopRated", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.TopRated.Hundred.NsPerOp)
+	})
+	printRow(file, "Collaborators", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Collaborators.Hundred.NsPerOp)
+	})
+	printRow(file, "Movie", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Movie.Hundred.NsPerOp)
+	})
+	printRow(file, "Wide", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Wide.Hundred.NsPerOp)
+	})
+	printRow(file, "List", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.List.Thousand.NsPerOp)
+	})
+	printRow(file, "ListPreload", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreload.Thousand.NsPerOp)
+	})
+	printRow(file, "ListPreloadLoader", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadLoader.Thousand.NsPerOp)
+	})
+	printRow(file, "ListNPlusOne", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListNPlusOne.Thousand.NsPerOp)
+	})
+	printRow(file, "ListJSON", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListJSON.Thousand.NsPerOp)
+	})
+	printRow(file, "Dashboard", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Dashboard.Thousand.NsPerOp)
+	})
+	printRow(file, "DashboardPreload", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreload.Thousand.NsPerOp)
+	})
+	printRow(file, "DashboardPreloadLoader", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadLoader.Thousand.NsPerOp)
+	})
+	printRow(file, "Details", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Details.Thousand.NsPerOp)
+	})
+	printRow(file, "DetailsNull", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DetailsNull.Thousand.NsPerOp)
+	})
+	printRow(file, "Search", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Search.Thousand.NsPerOp)
+	})
+	printRow(file, "Websearch", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Websearch.Thousand.NsPerOp)
+	})
+	printRow(file, "Facets", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Facets.Thousand.NsPerOp)
+	})
+	printRow(file, "FacetsBatch", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.FacetsBatch.Thousand.NsPerOp)
+	})
+	printRow(file, "TopRated", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.TopRated.Thousand.NsPerOp)
+	})
+	printRow(file, "Collaborators", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Collaborators.Thousand.NsPerOp)
+	})
+	printRow(file, "Movie", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Movie.Thousand.NsPerOp)
+	})
+	printRow(file, "Wide", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Wide.Thousand.NsPerOp)
+	})
+	printRow(file, "PreloadAny", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.Ten.NsPerOp)
+	})
+	printRow(file, "PreloadAny", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.Thousand.NsPerOp)
+	})
+	printRow(file, "PreloadAny", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.TenThousand.NsPerOp)
+	})
+	printRow(file, "PreloadAny", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.FiftyThousand.NsPerOp)
+	})
+	printRow(file, "PreloadUnnest", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.Ten.NsPerOp)
+	})
+	printRow(file, "PreloadUnnest", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.Thousand.NsPerOp)
+	})
+	printRow(file, "PreloadUnnest", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.TenThousand.NsPerOp)
+	})
+	printRow(file, "PreloadUnnest", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.FiftyThousand.NsPerOp)
+	})
+	printRow(file, "PreloadTempTable", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.Ten.NsPerOp)
+	})
+	printRow(file, "PreloadTempTable", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.Thousand.NsPerOp)
+	})
+	printRow(file, "PreloadTempTable", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.TenThousand.NsPerOp)
+	})
+	printRow(file, "PreloadTempTable", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.FiftyThousand.NsPerOp)
+	})
+	printRow(file, "PreloadBatch", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.Ten.NsPerOp)
+	})
+	printRow(file, "PreloadBatch", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.Thousand.NsPerOp)
+	})
+	printRow(file, "PreloadBatch", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.TenThousand.NsPerOp)
+	})
+	printRow(file, "PreloadBatch", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.FiftyThousand.NsPerOp)
+	})
+	printRow(file, "ListPreloadCached", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadCached.Ten.NsPerOp)
+	})
+	printRow(file, "ListPreloadCached", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadCached.Hundred.NsPerOp)
+	})
+	printRow(file, "ListPreloadCached", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadCached.Thousand.NsPerOp)
+	})
+	printRow(file, "DashboardPreloadCached", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadCached.Ten.NsPerOp)
+	})
+	printRow(file, "DashboardPreloadCached", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadCached.Hundred.NsPerOp)
+	})
+	printRow(file, "DashboardPreloadCached", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadCached.Thousand.NsPerOp)
+	})
+
+	fmt.Fprintf(file, `
+\bottomrule
+\end{tabular}
+\label{tab:benchmark_%s_nsperop}
+\end{table}
+	`, strings.ToLower(name))
+
+	return file.Close()
+}
+
+func bytesPerOpTable(o *Options, name string, framework benchflix.Framework, base benchflix.Framework) error {
+	file, err := o.CreateData(fmt.Sprintf("%s_bytesperop.tex", strings.ToLower(name)))
+	if err != nil {
+		return err
+	}
+
+	delta := fmt.Sprintf(`& ${\Delta M_{%s,SQL}}$`, name)
+	if reflect.DeepEqual(base, benchflix.Framework{}) {
+		delta = ""
+	}
+
+	fmt.Fprintf(file, `
+\begin{table}[ht]
+\centering
+\caption{%s: Speicherverbrauch pro Operation}
+\begin{tabular}{lrrrr}
+\toprule
+Szenario & Params & ${M_{%s}}$ & ${QA_{%s}}$ %s \\
+\midrule
+`, name, name, name, delta)
+
+	printRow(file, "List", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.List.Hundred.BytesPerOp)
+	})
+	printRow(file, "ListPreload", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreload.Hundred.BytesPerOp)
+	})
+	printRow(file, "ListPreloadLoader", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadLoader.Hundred.BytesPerOp)
+	})
+	printRow(file, "ListNPlusOne", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListNPlusOne.Hundred.BytesPerOp)
+	})
+	printRow(file, "ListJSON", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListJSON.Hundred.BytesPerOp)
+	})
+	printRow(file, "Dashboard", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Dashboard.Hundred.BytesPerOp)
+	})
+	printRow(file, "DashboardPreload", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreload.Hundred.BytesPerOp)
+	})
+	printRow(file, "DashboardPreloadLoader", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadLoader.Hundred.BytesPerOp)
+	})
+	printRow(file, "Details", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Details.Hundred.BytesPerOp)
+	})
+	printRow(file, "DetailsNull", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DetailsNull.Hundred.BytesPerOp)
+	})
+	printRow(file, "Search", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Search.Hundred.BytesPerOp)
+	})
+	printRow(file, "Websearch", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Websearch.Hundred.BytesPerOp)
+	})
+	printRow(file, "Facets", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Facets.Hundred.BytesPerOp)
+	})
+	printRow(file, "FacetsBatch", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.FacetsBatch.Hundred.BytesPerOp)
+	})
+	printRow(file, "TopRated", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.TopRated.Hundred.BytesPerOp)
+	})
+	printRow(file, "Collaborators", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Collaborators.Hundred.BytesPerOp)
+	})
+	printRow(file, "Movie", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Movie.Hundred.BytesPerOp)
+	})
+	printRow(file, "Wide", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Wide.Hundred.BytesPerOp)
+	})
+	printRow(file, "List", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.List.Thousand.BytesPerOp)
+	})
+	printRow(file, "ListPreload", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreload.Thousand.BytesPerOp)
+	})
+	printRow(file, "ListPreloadLoader", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadLoader.Thousand.BytesPerOp)
+	})
+	printRow(file, "ListNPlusOne", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListNPlusOne.Thousand.BytesPerOp)
+	})
+	printRow(file, "ListJSON", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListJSON.Thousand.BytesPerOp)
+	})
+	printRow(file, "Dashboard", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Dashboard.Thousand.BytesPerOp)
+	})
+	printRow(file, "DashboardPreload", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreload.Thousand.BytesPerOp)
+	})
+	printRow(file, "DashboardPreloadLoader", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadLoader.Thousand.BytesPerOp)
+	})
+	printRow(file, "Details", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Details.Thousand.BytesPerOp)
+	})
+	printRow(file, "DetailsNull", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DetailsNull.Thousand.BytesPerOp)
+	})
+	printRow(file, "Search", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Search.Thousand.BytesPerOp)
+	})
+	printRow(file, "Websearch", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Websearch.Thousand.BytesPerOp)
+	})
+	printRow(file, "Facets", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Facets.Thousand.BytesPerOp)
+	})
+	printRow(file, "FacetsBatch", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.FacetsBatch.Thousand.BytesPerOp)
+	})
+	printRow(file, "TopRated", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.TopRated.Thousand.BytesPerOp)
+	})
+	printRow(file, "Collaborators", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Collaborators.Thousand.BytesPerOp)
+	})
+	printRow(file, "Movie", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Movie.Thousand.BytesPerOp)
+	})
+	printRow(file, "Wide", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Wide.Thousand.BytesPerOp)
+	})
+	printRow(file, "PreloadAny", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.Ten.BytesPerOp)
+	})
+	printRow(file, "PreloadAny", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.Thousand.BytesPerOp)
+	})
+	printRow(file, "PreloadAny", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.TenThousand.BytesPerOp)
+	})
+	printRow(file, "PreloadAny", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.FiftyThousand.BytesPerOp)
+	})
+	printRow(file, "PreloadUnnest", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.Ten.BytesPerOp)
+	})
+	printRow(file, "PreloadUnnest", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.Thousand.BytesPerOp)
+	})
+	printRow(file, "PreloadUnnest", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.TenThousand.BytesPerOp)
+	})
+	printRow(file, "PreloadUnnest", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.FiftyThousand.BytesPerOp)
+	})
+	printRow(file, "PreloadTempTable", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.Ten.BytesPerOp)
+	})
+	printRow(file, "PreloadTempTable", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.Thousand.BytesPerOp)
+	})
+	printRow(file, "PreloadTempTable", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.TenThousand.BytesPerOp)
+	})
+	printRow(file, "PreloadTempTable", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.FiftyThousand.BytesPerOp)
+	})
+	printRow(file, "PreloadBatch", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.Ten.BytesPerOp)
+	})
+	printRow(file, "PreloadBatch", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.Thousand.BytesPerOp)
+	})
+	printRow(file, "PreloadBatch", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.TenThousand.BytesPerOp)
+	})
+	printRow(file, "PreloadBatch", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.FiftyThousand.BytesPerOp)
+	})
+	printRow(file, "ListPreloadCached", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadCached.Ten.BytesPerOp)
+	})
+	printRow(file, "ListPreloadCached", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadCached.Hundred.BytesPerOp)
+	})
+	printRow(file, "ListPreloadCached", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadCached.Thousand.BytesPerOp)
+	})
+	printRow(file, "DashboardPreloadCached", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadCached.Ten.BytesPerOp)
+	})
+	printRow(file, "DashboardPreloadCached", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadCached.Hundred.BytesPerOp)
+	})
+	printRow(file, "DashboardPreloadCached", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadCached.Thousand.BytesPerOp)
+	})
+
+	fmt.Fprintf(file, `
+\bottomrule
+\end{tabular}
+\label{tab:benchmark_%s_bytesperop}
+\end{table}
+	`, strings.ToLower(name))
+
+	return file.Close()
+}
+
+func allocsPerOpTable(o *Options, name string, framework benchflix.Framework, base benchflix.Framework) error {
+	file, err := o.CreateData(fmt.Sprintf("%s_allocsperop.tex", strings.ToLower(name)))
+	if err != nil {
+		return err
+	}
+
+	delta := fmt.Sprintf(`& ${\Delta M_{%s,SQL}}$`, name)
+	if reflect.DeepEqual(base, benchflix.Framework{}) {
+		delta = ""
+	}
+
+	fmt.Fprintf(file, `
+\begin{table}[ht]
+\centering
+\caption{%s: Allokationen pro Operation}
+\begin{tabular}{lrrrr}
+\toprule
+Szenario & Params & ${M_{%s}}$ & ${QA_{%s}}$ %s \\
+\midrule
+`, name, name, name, delta)
+
+	printRow(file, "List", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.List.Hundred.AllocsPerOp)
+	})
+	printRow(file, "ListPreload", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreload.Hundred.AllocsPerOp)
+	})
+	printRow(file, "ListPreloadLoader", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadLoader.Hundred.AllocsPerOp)
+	})
+	printRow(file, "ListNPlusOne", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListNPlusOne.Hundred.AllocsPerOp)
+	})
+	printRow(file, "ListJSON", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListJSON.Hundred.AllocsPerOp)
+	})
+	printRow(file, "Dashboard", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Dashboard.Hundred.AllocsPerOp)
+	})
+	printRow(file, "DashboardPreload", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreload.Hundred.AllocsPerOp)
+	})
+	printRow(file, "DashboardPreloadLoader", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadLoader.Hundred.AllocsPerOp)
+	})
+	printRow(file, "Details", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Details.Hundred.AllocsPerOp)
+	})
+	printRow(file, "DetailsNull", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DetailsNull.Hundred.AllocsPerOp)
+	})
+	printRow(file, "Search", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Search.Hundred.AllocsPerOp)
+	})
+	printRow(file, "Websearch", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Websearch.Hundred.AllocsPerOp)
+	})
+	printRow(file, "Facets", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Facets.Hundred.AllocsPerOp)
+	})
+	printRow(file, "FacetsBatch", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.FacetsBatch.Hundred.AllocsPerOp)
+	})
+	printRow(file, "TopRated", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.TopRated.Hundred.AllocsPerOp)
+	})
+	printRow(file, "Collaborators", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Collaborators.Hundred.AllocsPerOp)
+	})
+	printRow(file, "Movie", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Movie.Hundred.AllocsPerOp)
+	})
+	printRow(file, "Wide", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Wide.Hundred.AllocsPerOp)
+	})
+	printRow(file, "List", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.List.Thousand.AllocsPerOp)
+	})
+	printRow(file, "ListPreload", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreload.Thousand.AllocsPerOp)
+	})
+	printRow(file, "ListPreloadLoader", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadLoader.Thousand.AllocsPerOp)
+	})
+	printRow(file, "ListNPlusOne", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListNPlusOne.Thousand.AllocsPerOp)
+	})
+	printRow(file, "ListJSON", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListJSON.Thousand.AllocsPerOp)
+	})
+	printRow(file, "Dashboard", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Dashboard.Thousand.AllocsPerOp)
+	})
+	printRow(file, "DashboardPreload", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreload.Thousand.AllocsPerOp)
+	})
+	printRow(file, "DashboardPreloadLoader", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadLoader.Thousand.AllocsPerOp)
+	})
+	printRow(file, "Details", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Details.Thousand.AllocsPerOp)
+	})
+	printRow(file, "DetailsNull", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DetailsNull.Thousand.AllocsPerOp)
+	})
+	printRow(file, "Search", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Search.Thousand.AllocsPerOp)
+	})
+	printRow(file, "Websearch", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Websearch.Thousand.AllocsPerOp)
+	})
+	printRow(file, "Facets", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Facets.Thousand.AllocsPerOp)
+	})
+	printRow(file, "FacetsBatch", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.FacetsBatch.Thousand.AllocsPerOp)
+	})
+	printRow(file, "TopRated", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.TopRated.Thousand.AllocsPerOp)
+	})
+	printRow(file, "Collaborators", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Collaborators.Thousand.AllocsPerOp)
+	})
+	printRow(file, "Movie", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Movie.Thousand.AllocsPerOp)
+	})
+	printRow(file, "Wide", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.Wide.Thousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadAny", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.Ten.AllocsPerOp)
+	})
+	printRow(file, "PreloadAny", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.Thousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadAny", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.TenThousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadAny", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadAny.FiftyThousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadUnnest", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.Ten.AllocsPerOp)
+	})
+	printRow(file, "PreloadUnnest", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.Thousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadUnnest", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.TenThousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadUnnest", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadUnnest.FiftyThousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadTempTable", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.Ten.AllocsPerOp)
+	})
+	printRow(file, "PreloadTempTable", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.Thousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadTempTable", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.TenThousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadTempTable", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadTempTable.FiftyThousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadBatch", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.Ten.AllocsPerOp)
+	})
+	printRow(file, "PreloadBatch", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.Thousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadBatch", "10000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.TenThousand.AllocsPerOp)
+	})
+	printRow(file, "PreloadBatch", "50000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.PreloadBatch.FiftyThousand.AllocsPerOp)
+	})
+	printRow(file, "ListPreloadCached", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadCached.Ten.AllocsPerOp)
+	})
+	printRow(file, "ListPreloadCached", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadCached.Hundred.AllocsPerOp)
+	})
+	printRow(file, "ListPreloadCached", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.ListPreloadCached.Thousand.AllocsPerOp)
+	})
+	printRow(file, "DashboardPreloadCached", "10", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadCached.Ten.AllocsPerOp)
+	})
+	printRow(file, "DashboardPreloadCached", "100", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadCached.Hundred.AllocsPerOp)
+	})
+	printRow(file, "DashboardPreloadCached", "1000", framework, base, func(f benchflix.Framework) (stats.Quartiles, error) {
+		return stats.Quartile(f.DashboardPreloadCached.Thousand.AllocsPerOp)
+	})
+
+	fmt.Fprintf(file, `
+\bottomrule
+\end{tabular}
+\label{tab:benchmark_%s_allocsperop}
+\end{table}
+	`, strings.ToLower(name))
+
+	return file.Close()
+}
+
+func printRow(w io.Writer, szenario string, params string, framework, base benchflix.Framework, fn func(benchflix.Framework) (stats.Quartiles, error)) {
+	f, err := fn(framework)
+	if err != nil {
+		return
+	}
+
+	b, err := fn(base)
+	if err != nil {
+		if err == stats.ErrEmptyInput {
+			fmt.Fprintf(w, `
+	%s & %s & %g & %g \\`,
+				szenario, params, math.Round(f.Q2), math.Round(f.Q3-f.Q1))
+		}
+
+		return
+	}
+
+	fmt.Fprintf(w, `
+	%s & %s & %g & %g & %.1f\%% \\`,
+		szenario, params, math.Round(f.Q2), math.Round(f.Q3-f.Q1), math.Round(f.Q2/b.Q2*1000)/10-100)
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-sqlt/benchflix"
+	_ "github.com/go-sqlt/benchflix/gormflix"
+	_ "github.com/go-sqlt/benchflix/pgxflix"
+	_ "github.com/go-sqlt/benchflix/sqlcflix"
+	_ "github.com/go-sqlt/benchflix/sqlflix"
+	_ "github.com/go-sqlt/benchflix/sqltflix"
+	_ "github.com/go-sqlt/benchflix/sqlxflix"
+	_ "github.com/go-sqlt/benchflix/squirrelflix"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Adapter struct {
+	Name       string
+	Repository benchflix.Repository
+}
+
+type Node struct {
+	NodeType     string  `json:"Node Type"`
+	RelationName string  `json:"Relation Name"`
+	IndexName    string  `json:"Index Name"`
+	TotalCost    float64 `json:"Total Cost"`
+	Plans        []Node  `json:"Plans"`
+}
+
+func (n Node) Shape() string {
+	shape := n.NodeType
+
+	if n.RelationName != "" {
+		shape += " on " + n.RelationName
+	}
+
+	if n.IndexName != "" {
+		shape += " using " + n.IndexName
+	}
+
+	if len(n.Plans) == 0 {
+		return shape
+	}
+
+	children := make([]string, len(n.Plans))
+
+	for i, p := range n.Plans {
+		children[i] = p.Shape()
+	}
+
+	return shape + "(" + strings.Join(children, ", ") + ")"
+}
+
+// Szenario explains the query of one szenario for its params.
+type Szenario struct {
+	Name    string
+	Explain func(ctx context.Context, pool *pgxpool.Pool, adapters []Adapter, params benchflix.ParamSet, tolerance float64) error
+}
+
+func szenarioOf[P, R any](name string, query func(benchflix.Repository, context.Context, P) (R, error), params func(benchflix.ParamSet) []P) Szenario {
+	return Szenario{
+		Name: name,
+		Explain: func(ctx context.Context, pool *pgxpool.Pool, adapters []Adapter, p benchflix.ParamSet, tolerance float64) error {
+			return Explain(ctx, pool, adapters, name, params(p), query, tolerance)
+		},
+	}
+}
+
+var szenarios = []Szenario{
+	szenarioOf("List", benchflix.Repository.QueryList, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	szenarioOf("ListPreload", benchflix.Repository.QueryListPreload, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	szenarioOf("ListNPlusOne", benchflix.Repository.QueryListNPlusOne, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	szenarioOf("ListJSON", benchflix.Repository.QueryListJSON, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	szenarioOf("Details", benchflix.Repository.QueryDetails, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	szenarioOf("DetailsNull", benchflix.Repository.QueryDetailsNull, func(p benchflix.ParamSet) []benchflix.ListParams { return p.List }),
+	szenarioOf("Dashboard", benchflix.Repository.QueryDashboard, func(p benchflix.ParamSet) []benchflix.DashboardParams { return p.Dashboard }),
+	szenarioOf("DashboardPreload", benchflix.Repository.QueryDashboardPreload, func(p benchflix.ParamSet) []benchflix.DashboardParams { return p.Dashboard }),
+	szenarioOf("Facets", benchflix.Repository.QueryFacets, func(p benchflix.ParamSet) []benchflix.DashboardParams { return p.Dashboard }),
+	szenarioOf("FacetsBatch", benchflix.Repository.QueryFacetsBatch, func(p benchflix.ParamSet) []benchflix.DashboardParams { return p.Dashboard }),
+	szenarioOf("Search", benchflix.Repository.QuerySearch, func(p benchflix.ParamSet) []benchflix.SearchParams { return p.Search }),
+	szenarioOf("Websearch", benchflix.Repository.QuerySearch, func(p benchflix.ParamSet) []benchflix.SearchParams { return p.Websearch }),
+	szenarioOf("TopRated", benchflix.Repository.QueryTopRated, func(p benchflix.ParamSet) []benchflix.TopParams { return p.Top }),
+	szenarioOf("Collaborators", benchflix.Repository.QueryCollaborators, func(p benchflix.ParamSet) []benchflix.GraphParams { return p.Graph }),
+	szenarioOf("Movie", benchflix.Repository.QueryMovie, func(p benchflix.ParamSet) []int64 { return p.MovieIDs }),
+	szenarioOf("Wide", benchflix.Repository.QueryWide, func(p benchflix.ParamSet) []benchflix.WideParams { return p.Wide }),
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "explain:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	path := flag.String("params", "./params.json", "params file generated by cmd/params")
+	tolerance := flag.Float64("tolerance", 0.1, "relative difference in total cost that counts as divergent")
+	flag.Parse()
+
+	params, err := benchflix.LoadParamSet(*path)
+	if err != nil {
+		return err
+	}
+
+	conn, resource, err := benchflix.StartPostgres("Explain")
+	if err != nil {
+		return err
+	}
+
+	defer resource.Close()
+
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	defer pool.Close()
+
+	var (
+		adapters []Adapter
+		seen     = map[string]bool{}
+	)
+
+	for _, f := range benchflix.DefaultFrameworks {
+		// frameworks that only differ in options send the same sql
+		if seen[f.Adapter] {
+			continue
+		}
+
+		seen[f.Adapter] = true
+
+		repo, err := f.Open(conn, 1, 2, time.Minute, benchflix.WithInstrumentation())
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+
+		if closer, ok := repo.(io.Closer); ok {
+			defer closer.Close()
+		}
+
+		adapters = append(adapters, Adapter{Name: f.Name, Repository: repo})
+	}
+
+	for _, s := range szenarios {
+		if err = s.Explain(ctx, pool, adapters, params, *tolerance); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Explain prints, for one szenario, how often each adapter got each plan shape
+// and every param for which the adapters got different shapes or costs.
+func Explain[P, R any](ctx context.Context, pool *pgxpool.Pool, adapters []Adapter, szenario string, params []P, query func(benchflix.Repository, context.Context, P) (R, error), tolerance float64) error {
+	var (
+		shapes    []string
+		counts    = map[string]map[string]int{}
+		divergent []string
+	)
+
+	for i, p := range params {
+		var plans []benchflix.Plan
+
+		for _, a := range adapters {
+			statements, err := benchflix.CapturePlanned(ctx, a.Repository, p, query)
+			if errors.Is(err, benchflix.ErrSkip) {
+				continue
+			}
+
+			if err != nil {
+				return fmt.Errorf("%s %s[%d]: %w", a.Name, szenario, i, err)
+			}
+
+			plan := benchflix.Plan{Adapter: a.Name}
+
+			for _, s := range statements {
+				node, execution, err := ExplainStatement(ctx, pool, s)
+				if err != nil {
+					return fmt.Errorf("%s %s[%d]: %w\n%s", a.Name, szenario, i, err, s.SQL)
+				}
+
+				if plan.Shape != "" {
+					plan.Shape += "; "
+				}
+
+				plan.Shape += node.Shape()
+				plan.Cost += node.TotalCost
+				plan.Execution += execution
+			}
+
+			if _, ok := counts[plan.Shape]; !ok {
+				counts[plan.Shape] = map[string]int{}
+				shapes = append(shapes, plan.Shape)
+			}
+
+			counts[plan.Shape][a.Name]++
+
+			plans = append(plans, plan)
+		}
+
+		if benchflix.Divergent(plans, tolerance) {
+			divergent = append(divergent, Describe(i, p, plans, shapes))
+		}
+	}
+
+	fmt.Printf("== %s: %d of %d params divergent, %d plan shapes\n\n", szenario, len(divergent), len(params), len(shapes))
+
+	for i, shape := range shapes {
+		fmt.Printf("shape %d: %s\n\t", i+1, shape)
+
+		for _, a := range adapters {
+			if n := counts[shape][a.Name]; n > 0 {
+				fmt.Printf("%s=%d ", a.Name, n)
+			}
+		}
+
+		fmt.Print("\n\n")
+	}
+
+	for _, d := range divergent {
+		fmt.Println(d)
+	}
+
+	fmt.Println()
+
+	return nil
+}
+
+func ExplainStatement(ctx context.Context, pool *pgxpool.Pool, s benchflix.Statement) (Node, float64, error) {
+	var data []byte
+
+	if err := pool.QueryRow(ctx, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+s.SQL, s.Args...).Scan(&data); err != nil {
+		return Node{}, 0, err
+	}
+
+	var result []struct {
+		Plan          Node    `json:"Plan"`
+		ExecutionTime float64 `json:"Execution Time"`
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return Node{}, 0, err
+	}
+
+	if len(result) == 0 {
+		return Node{}, 0, errors.New("empty plan")
+	}
+
+	return result[0].Plan, result[0].ExecutionTime, nil
+}
+
+func Describe[P any](index int, params P, plans []benchflix.Plan, shapes []string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "[%d] %+v", index, params)
+
+	for _, p := range plans {
+		fmt.Fprintf(&b, "\n\t%-8s shape %d cost=%.2f exec=%.3fms", p.Adapter, slices.Index(shapes, p.Shape)+1, p.Cost, p.Execution)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-sqlt/benchflix"
+	"github.com/google/pprof/profile"
+)
+
+func main() {
+	dir := flag.String("dir", "data/profiles", "directory written by go test -profiles")
+	top := flag.Int("top", 5, "number of functions per group")
+	objects := flag.Bool("objects", false, "rank allocations by count instead of bytes")
+	flag.Parse()
+
+	files := benchflix.Must(filepath.Glob(filepath.Join(*dir, "*.pprof")))
+
+	slices.Sort(files)
+
+	for _, file := range files {
+		name, kind, _ := strings.Cut(strings.TrimSuffix(filepath.Base(file), ".pprof"), ".")
+
+		sample := "cpu"
+		if kind == "allocs" {
+			sample = "alloc_space"
+
+			if *objects {
+				sample = "alloc_objects"
+			}
+		}
+
+		f := benchflix.Must(os.Open(file))
+		p := benchflix.Must(profile.Parse(f))
+		_ = f.Close()
+
+		Print(name, p, sample, *top)
+	}
+}
+
+type Function struct {
+	Name  string
+	Value int64
+}
+
+func Print(name string, p *profile.Profile, sample string, top int) {
+	index := slices.IndexFunc(p.SampleType, func(t *profile.ValueType) bool { return t.Type == sample })
+	if index < 0 {
+		fmt.Fprintf(os.Stderr, "%s: no %s samples\n", name, sample)
+
+		return
+	}
+
+	grouped := benchflix.GroupProfile(p, index)
+	if grouped.Total <= 0 {
+		return
+	}
+
+	total := grouped.Total
+
+	fmt.Printf("== %s %s total %s\n", strings.ReplaceAll(name, "_", "/"), sample, Format(total, p.SampleType[index].Unit))
+
+	var names []string
+
+	for _, g := range benchflix.ProfileGroups {
+		names = append(names, g.Name)
+	}
+
+	names = append(names, benchflix.OtherGroup)
+
+	for _, g := range names {
+		fmt.Printf("%-10s %6.1f%%\n", g, Percent(grouped.Groups[g], total))
+
+		var ranked []Function
+
+		for f, v := range grouped.Functions[g] {
+			ranked = append(ranked, Function{Name: f, Value: v})
+		}
+
+		slices.SortFunc(ranked, func(a, b Function) int {
+			return cmp.Or(cmp.Compare(b.Value, a.Value), strings.Compare(a.Name, b.Name))
+		})
+
+		for _, f := range ranked[:min(top, len(ranked))] {
+			fmt.Printf("\t%6.1f%% %s\n", Percent(f.Value, total), f.Name)
+		}
+	}
+
+	fmt.Println()
+}
+
+func Percent(value, total int64) float64 {
+	return float64(value) / float64(total) * 100
+}
+
+func Format(value int64, unit string) string {
+	switch unit {
+	case "nanoseconds":
+		return fmt.Sprintf("%.2fs", float64(value)/1e9)
+	case "bytes":
+		return fmt.Sprintf("%.1fMB", float64(value)/(1<<20))
+	default:
+		return fmt.Sprintf("%d %s", value, unit)
+	}
+}
@@ -0,0 +1,12 @@
+// Command run is a shorthand for benchflix run.
+package main
+
+import (
+	"os"
+
+	"github.com/go-sqlt/benchflix/cli"
+)
+
+func main() {
+	os.Exit(cli.Main(append([]string{"run"}, os.Args[1:]...)))
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/snapshot-chromedp/render"
+	"github.com/go-sqlt/benchflix"
+)
+
+// Curve is one line written by go test -warmup-curves.
+type Curve struct {
+	Benchmark string  `json:"benchmark"`
+	N         int     `json:"n"`
+	Calls     int     `json:"calls"`
+	Duration  int64   `json:"duration_ns"`
+	Window    int     `json:"window"`
+	Steady    bool    `json:"steady"`
+	Curve     []int64 `json:"curve_ns"`
+}
+
+func main() {
+	path := flag.String("curves", "warmup.jsonl", "file written by go test -warmup-curves")
+	chart := flag.Bool("chart", false, "render a chart per szenario to data/")
+	flag.Parse()
+
+	file := benchflix.Must(os.Open(*path))
+
+	defer file.Close()
+
+	var (
+		szenarios []string
+		curves    = map[string]map[string]Curve{}
+		scanner   = bufio.NewScanner(file)
+	)
+
+	scanner.Buffer(nil, 64<<20)
+
+	for scanner.Scan() {
+		var c Curve
+
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			panic(err)
+		}
+
+		// Benchmark/<framework>/pool=<min-max>/<szenario>..., the pool stays part of the szenario
+		parts := strings.SplitN(c.Benchmark, "/", 3)
+		if len(parts) < 3 {
+			continue
+		}
+
+		framework, szenario := parts[1], parts[2]
+
+		if _, ok := curves[szenario]; !ok {
+			curves[szenario] = map[string]Curve{}
+			szenarios = append(szenarios, szenario)
+		}
+
+		// every round of b.N writes a line, the last one is the measured run
+		curves[szenario][framework] = c
+	}
+
+	if err := scanner.Err(); err != nil {
+		panic(err)
+	}
+
+	for _, szenario := range szenarios {
+		Print(szenario, curves[szenario])
+
+		if *chart {
+			Render(szenario, curves[szenario])
+		}
+	}
+}
+
+func Frameworks(curves map[string]Curve) []string {
+	frameworks := make([]string, 0, len(curves))
+
+	for f := range curves {
+		frameworks = append(frameworks, f)
+	}
+
+	slices.Sort(frameworks)
+
+	return frameworks
+}
+
+func Print(szenario string, curves map[string]Curve) {
+	fmt.Printf("== %s\n", szenario)
+	fmt.Printf("%-12s %10s %10s %7s %12s %12s\n", "framework", "calls", "duration", "steady", "first", "last")
+
+	for _, f := range Frameworks(curves) {
+		c := curves[f]
+
+		var first, last time.Duration
+
+		if len(c.Curve) > 0 {
+			first, last = time.Duration(c.Curve[0]), time.Duration(c.Curve[len(c.Curve)-1])
+		}
+
+		fmt.Printf("%-12s %10d %10s %7t %12s %12s\n", f, c.Calls, time.Duration(c.Duration).Round(time.Millisecond), c.Steady, first, last)
+	}
+
+	fmt.Println()
+}
+
+func Render(szenario string, curves map[string]Curve) {
+	chart := charts.NewLine()
+	chart.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: "Warmup " + szenario,
+		}),
+		charts.WithAnimation(false),
+		charts.WithInitializationOpts(opts.Initialization{
+			BackgroundColor: "#FFFFFF",
+		}),
+		charts.WithXAxisOpts(opts.XAxis{Name: "calls"}),
+		charts.WithYAxisOpts(opts.YAxis{Name: "ns/call"}),
+	)
+
+	var (
+		calls  []string
+		series = map[string][]opts.LineData{}
+	)
+
+	for _, f := range Frameworks(curves) {
+		c := curves[f]
+
+		for i, ns := range c.Curve {
+			if i == len(calls) {
+				calls = append(calls, strconv.Itoa((i+1)*c.Window))
+			}
+
+			series[f] = append(series[f], opts.LineData{Value: ns})
+		}
+	}
+
+	chart.SetXAxis(calls)
+
+	for _, f := range Frameworks(curves) {
+		chart.AddSeries(f, series[f])
+	}
+
+	output := "data/Warmup_" + strings.ReplaceAll(szenario, "/", "_") + ".png"
+
+	if err := render.MakeChartSnapshot(chart.RenderContent(), output); err != nil {
+		panic(err)
+	}
+}
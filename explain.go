@@ -0,0 +1,98 @@
+package benchflix
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Statement is a statement a repository sent, see CapturePlanned.
+type Statement struct {
+	SQL  string
+	Args []any
+}
+
+// Plan is the combined plan of all statements one adapter sent for one param.
+type Plan struct {
+	Adapter   string
+	Shape     string
+	Cost      float64
+	Execution float64
+}
+
+// CapturePlanned runs the query and returns the statements that were planned,
+// i.e. everything except transaction control, temp tables and copies. The
+// repository has to be opened WithInstrumentation. A query that succeeds
+// without a planned statement is an error, its adapter dropped ctx.
+func CapturePlanned[P, R any](ctx context.Context, repo Repository, params P, query func(Repository, context.Context, P) (R, error)) ([]Statement, error) {
+	var (
+		mu         sync.Mutex
+		statements []Statement
+	)
+
+	ctx = CaptureStatements(ctx, func(sql string, args []any) {
+		switch Keyword(sql) {
+		case "SELECT", "WITH":
+		default:
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		statements = append(statements, Statement{SQL: sql, Args: args})
+	})
+
+	if _, err := query(repo, ctx, params); err != nil {
+		return nil, err
+	}
+
+	if len(statements) == 0 {
+		return nil, errors.New("no statements captured")
+	}
+
+	return statements, nil
+}
+
+// Keyword returns the first keyword of the statement in upper case, after
+// leading comments such as the "-- name:" header of sqlc.
+func Keyword(sql string) string {
+	for {
+		sql = strings.TrimSpace(sql)
+
+		switch {
+		case strings.HasPrefix(sql, "--"):
+			_, sql, _ = strings.Cut(sql, "\n")
+		case strings.HasPrefix(sql, "/*"):
+			_, sql, _ = strings.Cut(sql, "*/")
+		default:
+			fields := strings.Fields(sql)
+			if len(fields) == 0 {
+				return ""
+			}
+
+			return strings.ToUpper(fields[0])
+		}
+	}
+}
+
+// Divergent reports whether the plans differ in shape or their total costs
+// differ by more than tolerance relative to the cheapest.
+func Divergent(plans []Plan, tolerance float64) bool {
+	if len(plans) < 2 {
+		return false
+	}
+
+	low, high := plans[0].Cost, plans[0].Cost
+
+	for _, p := range plans[1:] {
+		if p.Shape != plans[0].Shape {
+			return true
+		}
+
+		low, high = min(low, p.Cost), max(high, p.Cost)
+	}
+
+	return low > 0 && high/low-1 > tolerance
+}
@@ -7,10 +7,10 @@ require (
 	github.com/go-echarts/go-echarts/v2 v2.6.1
 	github.com/go-echarts/snapshot-chromedp v0.0.5
 	github.com/go-sqlt/sqlt v0.6.2
+	github.com/google/pprof v0.0.0-20251114195745-4902fdda35c8
 	github.com/jackc/pgx/v5 v5.7.5
 	github.com/montanaflynn/stats v0.7.1
 	github.com/ory/dockertest v3.3.5+incompatible
-	golang.org/x/tools v0.35.0
 )
 
 require (
@@ -38,7 +38,7 @@ require (
 	github.com/docker/go-connections v0.5.0 // indirect
 	github.com/docker/go-units v0.5.0 // indirect
 	github.com/go-sqlt/datahash v0.0.11 // indirect
-	github.com/go-sqlt/structscan v0.0.18 // indirect
+	github.com/go-sqlt/structscan v0.0.18
 	github.com/google/go-cmp v0.7.0 // indirect
 	github.com/google/safehtml v0.1.0 // indirect
 	github.com/gotestyourself/gotestyourself v2.2.0+incompatible // indirect
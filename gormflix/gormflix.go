@@ -3,10 +3,13 @@ package gormflix
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/go-sqlt/benchflix"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/lib/pq"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -20,19 +23,179 @@ type Movie struct {
 	Directors []*Person `gorm:"many2many:movie_directors"`
 }
 
+type JSONMovie struct {
+	ID        int64
+	Title     string
+	AddedAt   time.Time
+	Rating    float64
+	Directors []benchflix.Person `gorm:"serializer:json"`
+}
+
+type MovieDetails struct {
+	ID       int64 `gorm:"primaryKey"`
+	Title    string
+	AddedAt  time.Time
+	Rating   float64
+	Runtime  *int64
+	Budget   *int64
+	Tagline  *string
+	Metadata *benchflix.Metadata `gorm:"serializer:json"`
+}
+
+type MovieDetailsNull struct {
+	ID       int64 `gorm:"primaryKey"`
+	Title    string
+	AddedAt  time.Time
+	Rating   float64
+	Runtime  sql.NullInt64
+	Budget   sql.NullInt64
+	Tagline  sql.NullString
+	Metadata *benchflix.Metadata `gorm:"serializer:json"`
+}
+
+type SearchResult struct {
+	ID        int64
+	Title     string
+	AddedAt   time.Time
+	Rating    float64
+	Directors pq.StringArray `gorm:"type:text[]"`
+	Rank      float64
+	Headline  string
+}
+
+type FacetRow struct {
+	Facet string
+	Value int64
+	Count int64
+}
+
+type TopMovie struct {
+	ID        int64
+	Title     string
+	AddedAt   time.Time
+	Rating    float64
+	Year      int64
+	Position  int64
+	Directors pq.StringArray  `gorm:"type:text[]"`
+	Ratings   pq.Float64Array `gorm:"type:float8[]"`
+}
+
+type Collaborator struct {
+	PersonID int64
+	Name     string
+	Depth    int64
+	Movies   pq.StringArray `gorm:"type:text[]"`
+}
+
+type WideRow struct {
+	ID     int64
+	Int01  int64
+	Int02  int64
+	Int03  int64
+	Int04  int64
+	Int05  int64
+	Int06  int64
+	Int07  int64
+	Int08  int64
+	Int09  int64
+	Int10  int64
+	Int11  int64
+	Int12  int64
+	Num01  float64
+	Num02  float64
+	Num03  float64
+	Num04  float64
+	Num05  float64
+	Num06  float64
+	Num07  float64
+	Num08  float64
+	Num09  float64
+	Num10  float64
+	Num11  float64
+	Num12  float64
+	Time01 time.Time
+	Time02 time.Time
+	Time03 time.Time
+	Time04 time.Time
+	Time05 time.Time
+	Time06 time.Time
+	Time07 time.Time
+	Time08 time.Time
+	Time09 time.Time
+	Time10 time.Time
+	Time11 time.Time
+	Time12 time.Time
+	Tags01 pq.StringArray `gorm:"type:text[]"`
+	Tags02 pq.StringArray `gorm:"type:text[]"`
+	Tags03 pq.StringArray `gorm:"type:text[]"`
+	Tags04 pq.StringArray `gorm:"type:text[]"`
+	Tags05 pq.StringArray `gorm:"type:text[]"`
+	Tags06 pq.StringArray `gorm:"type:text[]"`
+	Tags07 pq.StringArray `gorm:"type:text[]"`
+	Tags08 pq.StringArray `gorm:"type:text[]"`
+	Tags09 pq.StringArray `gorm:"type:text[]"`
+	Tags10 pq.StringArray `gorm:"type:text[]"`
+	Tags11 pq.StringArray `gorm:"type:text[]"`
+	Tags12 pq.StringArray `gorm:"type:text[]"`
+	Note01 *string
+	Note02 *string
+	Note03 *string
+	Note04 *string
+	Note05 *string
+	Note06 *string
+	Note07 *string
+	Note08 *string
+	Note09 *string
+	Note10 *string
+	Note11 *string
+	Note12 *string
+}
+
+type MovieDirectors struct {
+	MovieID   int64
+	Directors pq.StringArray `gorm:"type:text[]"`
+}
+
 type Person struct {
 	ID   int64  `gorm:"primaryKey"`
 	Name string `gorm:"unique;not null;index"`
 }
 
-func NewRepository(conn string, min, max int, idle time.Duration) benchflix.Repository {
-	db := benchflix.Must(gorm.Open(postgres.Open(conn), &gorm.Config{
+func init() {
+	benchflix.RegisterAdapter("gorm", Open)
+}
+
+func NewRepository(conn string, min, max int, idle time.Duration, opts ...benchflix.Option) benchflix.Repository {
+	return benchflix.Must(Open(conn, min, max, idle, opts...))
+}
+
+// Open is NewRepository returning errors.
+func Open(conn string, min, max int, idle time.Duration, opts ...benchflix.Option) (benchflix.Repository, error) {
+	connector, err := benchflix.NewOptions(opts...).Connector(stdlib.GetDefaultDriver(), conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return OpenDB(sql.OpenDB(connector), min, max, idle)
+}
+
+// OpenDB opens the repository on a database/sql pool, e.g. one of a test driver.
+func OpenDB(conn *sql.DB, min, max int, idle time.Duration) (benchflix.Repository, error) {
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: conn,
+	}), &gorm.Config{
 		Logger:                 logger.Default.LogMode(logger.Silent),
 		SkipDefaultTransaction: true,
 		PrepareStmt:            true,
-	}))
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	sqldb := benchflix.Must(db.DB())
+	sqldb, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
 
 	sqldb.SetMaxOpenConns(max)
 	sqldb.SetMaxIdleConns(min)
@@ -40,11 +203,12 @@ func NewRepository(conn string, min, max int, idle time.Duration) benchflix.Repo
 
 	return Repository{
 		DB: db,
-	}
+	}, nil
 }
 
 type Repository struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Loader *benchflix.Loader[int64, []string]
 }
 
 //nolint:maintidx
@@ -55,9 +219,15 @@ func (r Repository) QueryList(ctx context.Context, params benchflix.ListParams)
 func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
 	var rows = make([]Movie, 0, params.Limit)
 
-	if err := r.DB.Preload("Directors", func(db *gorm.DB) *gorm.DB {
-		return db.Order("people.name DESC")
-	}).Raw(`
+	query := r.DB.WithContext(ctx)
+
+	if r.Loader == nil {
+		query = query.Preload("Directors", func(db *gorm.DB) *gorm.DB {
+			return db.Order("people.name DESC")
+		})
+	}
+
+	if err := query.Raw(`
 		SELECT
 			m.id
 			, m.title
@@ -111,6 +281,133 @@ func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListP
 		}
 	}
 
+	if r.Loader == nil {
+		return movies, nil
+	}
+
+	return r.loadMovieDirectors(ctx, movies)
+}
+
+func (r Repository) QueryListNPlusOne(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	var rows = make([]Movie, 0, params.Limit)
+
+	if err := r.DB.WithContext(ctx).Raw(`
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+		FROM movies m
+		WHERE
+			(
+				@search = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', @search)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', @search)
+				)
+			)
+			AND (@year_added = 0 OR EXTRACT(YEAR FROM m.added_at) = @year_added)
+			AND (@min_rating = 0 OR m.rating >= @min_rating)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN @limit BETWEEN 1 AND 1000 THEN @limit ELSE 1000 END;
+	`, sql.Named("search", params.Search), sql.Named("year_added", params.YearAdded),
+		sql.Named("min_rating", params.MinRating), sql.Named("limit", params.Limit)).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	movies := make([]benchflix.Movie, len(rows))
+
+	for i := range rows {
+		if err := r.DB.WithContext(ctx).Model(&rows[i]).Order("people.name").Association("Directors").Find(&rows[i].Directors); err != nil {
+			return nil, err
+		}
+
+		movies[i] = benchflix.Movie{
+			ID:      rows[i].ID,
+			Title:   rows[i].Title,
+			AddedAt: rows[i].AddedAt,
+			Rating:  rows[i].Rating,
+		}
+
+		if len(rows[i].Directors) == 0 {
+			continue
+		}
+
+		movies[i].Directors = make([]string, len(rows[i].Directors))
+
+		for j, d := range rows[i].Directors {
+			movies[i].Directors[j] = d.Name
+		}
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QueryListJSON(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	var rows = make([]JSONMovie, 0, params.Limit)
+
+	if err := r.DB.WithContext(ctx).Raw(`
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, COALESCE(d.directors, '[]') AS directors
+		FROM movies m
+		LEFT JOIN LATERAL (
+			SELECT json_agg(json_build_object('id', p.id, 'name', p.name) ORDER BY p.name) AS directors
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+		) d ON true
+		WHERE
+			(
+				@search = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', @search)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', @search)
+				)
+			)
+			AND (@year_added = 0 OR EXTRACT(YEAR FROM m.added_at) = @year_added)
+			AND (@min_rating = 0 OR m.rating >= @min_rating)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN @limit BETWEEN 1 AND 1000 THEN @limit ELSE 1000 END;
+	`, sql.Named("search", params.Search), sql.Named("year_added", params.YearAdded),
+		sql.Named("min_rating", params.MinRating), sql.Named("limit", params.Limit)).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	movies := make([]benchflix.Movie, len(rows))
+
+	for i, m := range rows {
+		movies[i] = benchflix.Movie{
+			ID:      m.ID,
+			Title:   m.Title,
+			AddedAt: m.AddedAt,
+			Rating:  m.Rating,
+		}
+
+		if len(m.Directors) == 0 {
+			continue
+		}
+
+		movies[i].Directors = make([]string, len(m.Directors))
+
+		for j, d := range m.Directors {
+			movies[i].Directors[j] = d.Name
+		}
+	}
+
 	return movies, nil
 }
 
@@ -122,9 +419,9 @@ func (r Repository) QueryDashboard(ctx context.Context, params benchflix.Dashboa
 func (r Repository) QueryDashboardPreload(ctx context.Context, params benchflix.DashboardParams) ([]benchflix.Movie, error) {
 	var rows = make([]Movie, 0, params.Limit)
 
-	query := r.DB.Table("movies")
+	query := r.DB.WithContext(ctx).Table("movies")
 
-	if params.WithDirectors {
+	if params.WithDirectors && r.Loader == nil {
 		query = query.Preload("Directors", func(db *gorm.DB) *gorm.DB {
 			return db.Order("people.name DESC")
 		})
@@ -203,5 +500,583 @@ func (r Repository) QueryDashboardPreload(ctx context.Context, params benchflix.
 		}
 	}
 
+	if !params.WithDirectors || r.Loader == nil {
+		return movies, nil
+	}
+
+	return r.loadMovieDirectors(ctx, movies)
+}
+
+func (r Repository) QueryDetails(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	var rows = make([]MovieDetails, 0, params.Limit)
+
+	if err := r.DB.WithContext(ctx).Raw(`
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, m.runtime
+			, m.budget
+			, m.tagline
+			, m.metadata
+		FROM movies m
+		WHERE
+			(
+				@search = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', @search)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', @search)
+				)
+			)
+			AND (@year_added = 0 OR EXTRACT(YEAR FROM m.added_at) = @year_added)
+			AND (@min_rating = 0 OR m.rating >= @min_rating)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN @limit BETWEEN 1 AND 1000 THEN @limit ELSE 1000 END;
+	`, sql.Named("search", params.Search), sql.Named("year_added", params.YearAdded),
+		sql.Named("min_rating", params.MinRating), sql.Named("limit", params.Limit)).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	movies := make([]benchflix.Movie, len(rows))
+
+	for i, m := range rows {
+		movies[i] = benchflix.Movie{
+			ID:       m.ID,
+			Title:    m.Title,
+			AddedAt:  m.AddedAt,
+			Rating:   m.Rating,
+			Runtime:  m.Runtime,
+			Budget:   m.Budget,
+			Tagline:  m.Tagline,
+			Metadata: m.Metadata,
+		}
+	}
+
 	return movies, nil
 }
+
+func (r Repository) QueryDetailsNull(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	var rows = make([]MovieDetailsNull, 0, params.Limit)
+
+	if err := r.DB.WithContext(ctx).Raw(`
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, m.runtime
+			, m.budget
+			, m.tagline
+			, m.metadata
+		FROM movies m
+		WHERE
+			(
+				@search = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', @search)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', @search)
+				)
+			)
+			AND (@year_added = 0 OR EXTRACT(YEAR FROM m.added_at) = @year_added)
+			AND (@min_rating = 0 OR m.rating >= @min_rating)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN @limit BETWEEN 1 AND 1000 THEN @limit ELSE 1000 END;
+	`, sql.Named("search", params.Search), sql.Named("year_added", params.YearAdded),
+		sql.Named("min_rating", params.MinRating), sql.Named("limit", params.Limit)).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	movies := make([]benchflix.Movie, len(rows))
+
+	for i, m := range rows {
+		movies[i] = benchflix.Movie{
+			ID:       m.ID,
+			Title:    m.Title,
+			AddedAt:  m.AddedAt,
+			Rating:   m.Rating,
+			Metadata: m.Metadata,
+		}
+
+		if m.Runtime.Valid {
+			movies[i].Runtime = &m.Runtime.Int64
+		}
+
+		if m.Budget.Valid {
+			movies[i].Budget = &m.Budget.Int64
+		}
+
+		if m.Tagline.Valid {
+			movies[i].Tagline = &m.Tagline.String
+		}
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QuerySearch(ctx context.Context, params benchflix.SearchParams) ([]benchflix.SearchResult, error) {
+	var rows = make([]SearchResult, 0, params.Limit)
+
+	tsquery := "plainto_tsquery"
+
+	if params.Websearch {
+		tsquery = "websearch_to_tsquery"
+	}
+
+	query := r.DB.WithContext(ctx).Table("movies AS m").
+		Select(`
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, d.directors
+			, ts_rank(
+				setweight(to_tsvector('simple', m.title), 'A')
+				|| setweight(to_tsvector('simple', COALESCE(d.names, '')), 'B'),
+				q.query
+			) AS rank
+			, ts_headline('simple', m.title || ' / ' || COALESCE(d.names, ''), q.query) AS headline
+		`).
+		Joins("CROSS JOIN "+tsquery+"('simple', ?) AS q (query)", params.Search).
+		Joins(`LEFT JOIN LATERAL (
+			SELECT
+				ARRAY_AGG(p.name ORDER BY p.name) AS directors
+				, STRING_AGG(p.name, ' ' ORDER BY p.name) AS names
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+		) d ON true`).
+		Where(`(
+			to_tsvector('simple', m.title) @@ q.query
+			OR EXISTS (
+				SELECT 1 FROM movie_directors md
+				JOIN people p ON p.id = md.person_id
+				WHERE md.movie_id = m.id
+				AND to_tsvector('simple', p.name) @@ q.query
+			)
+		)`).
+		Order("rank DESC, m.id")
+
+	if params.Limit < 1 || params.Limit > 1000 {
+		query = query.Limit(1000)
+	} else {
+		query = query.Limit(int(params.Limit))
+	}
+
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]benchflix.SearchResult, len(rows))
+
+	for i, s := range rows {
+		results[i] = benchflix.SearchResult{
+			Movie: benchflix.Movie{
+				ID:        s.ID,
+				Title:     s.Title,
+				AddedAt:   s.AddedAt,
+				Rating:    s.Rating,
+				Directors: s.Directors,
+			},
+			Rank:     s.Rank,
+			Headline: s.Headline,
+		}
+	}
+
+	return results, nil
+}
+
+func (r Repository) QueryFacets(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	var (
+		result benchflix.FacetResult
+		rows   []FacetRow
+		err    error
+	)
+
+	result.Movies, err = r.QueryDashboardPreload(ctx, params)
+	if err != nil {
+		return result, err
+	}
+
+	query := r.DB.WithContext(ctx).Table("movies").Select(`
+		CASE WHEN GROUPING(EXTRACT(YEAR FROM movies.added_at)) = 0 THEN 'year' ELSE 'rating' END AS facet
+		, CAST(COALESCE(EXTRACT(YEAR FROM movies.added_at), FLOOR(movies.rating)) AS INT8) AS value
+		, COUNT(*) AS count
+	`)
+
+	if params.Search != "" {
+		query = query.Where(`(
+			to_tsvector('simple', movies.title) @@ plainto_tsquery('simple', @search)
+			OR EXISTS (
+				SELECT 1 FROM movie_directors md
+				JOIN people p ON p.id = md.person_id
+				WHERE md.movie_id = movies.id
+				AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', @search)
+			)
+		)`, sql.Named("search", params.Search))
+	}
+
+	if params.YearAdded != 0 {
+		query = query.Where("EXTRACT(YEAR FROM movies.added_at) = ?", params.YearAdded)
+	}
+
+	if params.MinRating != 0 {
+		query = query.Where("movies.rating >= ?", params.MinRating)
+	}
+
+	if err = query.
+		Group("GROUPING SETS ((EXTRACT(YEAR FROM movies.added_at)), (FLOOR(movies.rating)))").
+		Order("facet, value").
+		Scan(&rows).Error; err != nil {
+		return result, err
+	}
+
+	for _, row := range rows {
+		f := benchflix.Facet{Value: row.Value, Count: row.Count}
+
+		if row.Facet == "year" {
+			result.Years = append(result.Years, f)
+		} else {
+			result.Ratings = append(result.Ratings, f)
+		}
+	}
+
+	return result, nil
+}
+
+func (r Repository) QueryFacetsBatch(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	return benchflix.FacetResult{}, benchflix.ErrSkip
+}
+
+func (r Repository) QueryTopRated(ctx context.Context, params benchflix.TopParams) ([]benchflix.TopMovie, error) {
+	var rows []TopMovie
+
+	directorRatings := r.DB.WithContext(ctx).Table("movie_directors AS md").
+		Select("md.person_id, CAST(AVG(m.rating) AS FLOAT8) AS avg_rating").
+		Joins("JOIN movies m ON m.id = md.movie_id").
+		Group("md.person_id")
+
+	ranked := r.DB.WithContext(ctx).Table("movies AS m").
+		Select(`
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, CAST(EXTRACT(YEAR FROM m.added_at) AS INT8) AS year
+			, ROW_NUMBER() OVER (PARTITION BY EXTRACT(YEAR FROM m.added_at) ORDER BY m.rating DESC, m.id) AS position
+		`)
+
+	if params.MinRating != 0 {
+		ranked = ranked.Where("m.rating >= ?", params.MinRating)
+	}
+
+	perYear := params.PerYear
+
+	if perYear < 1 || perYear > 10 {
+		perYear = 10
+	}
+
+	if err := r.DB.WithContext(ctx).Table("(?) AS r", ranked).
+		Select("r.id, r.title, r.added_at, r.rating, r.year, r.position, d.directors, d.ratings").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT
+				ARRAY_AGG(p.name ORDER BY p.name) AS directors
+				, ARRAY_AGG(dr.avg_rating ORDER BY p.name) AS ratings
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			JOIN (?) AS dr ON dr.person_id = md.person_id
+			WHERE md.movie_id = r.id
+		) d ON true`, directorRatings).
+		Where("r.position <= ?", perYear).
+		Order("r.year, r.position").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	movies := make([]benchflix.TopMovie, len(rows))
+
+	for i, m := range rows {
+		movies[i] = benchflix.TopMovie{
+			Movie: benchflix.Movie{
+				ID:        m.ID,
+				Title:     m.Title,
+				AddedAt:   m.AddedAt,
+				Rating:    m.Rating,
+				Directors: m.Directors,
+			},
+			Year:            m.Year,
+			Position:        m.Position,
+			DirectorRatings: m.Ratings,
+		}
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QueryCollaborators(ctx context.Context, params benchflix.GraphParams) ([]benchflix.Collaborator, error) {
+	var rows []Collaborator
+
+	if err := r.DB.WithContext(ctx).Raw(`
+		WITH RECURSIVE graph (person_id, depth, path, movies) AS (
+			SELECT
+				CAST(p.id AS INT8)
+				, CAST(0 AS INT8)
+				, ARRAY[CAST(p.id AS INT8)]
+				, CAST(ARRAY[] AS TEXT[])
+			FROM people p
+			WHERE p.name = @director
+			UNION ALL
+			SELECT
+				CAST(b.person_id AS INT8)
+				, g.depth + 1
+				, g.path || CAST(b.person_id AS INT8)
+				, g.movies || m.title
+			FROM graph g
+			JOIN movie_directors a ON a.person_id = g.person_id
+			JOIN movie_directors b ON b.movie_id = a.movie_id
+			JOIN movies m ON m.id = a.movie_id
+			WHERE g.depth < CASE WHEN @depth BETWEEN 1 AND 3 THEN @depth ELSE 3 END
+			AND b.person_id <> ALL (g.path)
+		)
+		SELECT
+			c.person_id
+			, p.name
+			, c.depth
+			, c.movies
+		FROM (
+			SELECT DISTINCT ON (g.person_id)
+				g.person_id
+				, g.depth
+				, g.movies
+			FROM graph g
+			WHERE g.depth > 0
+			ORDER BY g.person_id, g.depth, g.movies
+		) c
+		JOIN people p ON p.id = c.person_id
+		ORDER BY c.depth, p.name;
+	`, sql.Named("director", params.Director), sql.Named("depth", params.Depth)).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	collaborators := make([]benchflix.Collaborator, len(rows))
+
+	for i, c := range rows {
+		collaborators[i] = benchflix.Collaborator{
+			ID:     c.PersonID,
+			Name:   c.Name,
+			Depth:  c.Depth,
+			Movies: c.Movies,
+		}
+	}
+
+	return collaborators, nil
+}
+
+func (r Repository) QueryMovie(ctx context.Context, id int64) (benchflix.Movie, error) {
+	var row Movie
+
+	err := r.DB.WithContext(ctx).Preload("Directors", func(db *gorm.DB) *gorm.DB {
+		return db.Order("people.name")
+	}).First(&row, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return benchflix.Movie{}, benchflix.ErrNotFound
+	}
+
+	if err != nil {
+		return benchflix.Movie{}, err
+	}
+
+	movie := benchflix.Movie{
+		ID:      row.ID,
+		Title:   row.Title,
+		AddedAt: row.AddedAt,
+		Rating:  row.Rating,
+	}
+
+	for _, d := range row.Directors {
+		movie.Directors = append(movie.Directors, d.Name)
+	}
+
+	return movie, nil
+}
+
+func (r Repository) QueryWide(ctx context.Context, params benchflix.WideParams) ([]benchflix.WideRow, error) {
+	var rows = make([]WideRow, 0, params.Limit)
+
+	query := r.DB.WithContext(ctx).Where("id > ?", params.After).Order("id")
+
+	if params.Limit < 1 || params.Limit > 1000 {
+		query = query.Limit(1000)
+	} else {
+		query = query.Limit(int(params.Limit))
+	}
+
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	wide := make([]benchflix.WideRow, len(rows))
+
+	for i, w := range rows {
+		wide[i] = benchflix.WideRow{
+			ID:     w.ID,
+			Int01:  w.Int01,
+			Int02:  w.Int02,
+			Int03:  w.Int03,
+			Int04:  w.Int04,
+			Int05:  w.Int05,
+			Int06:  w.Int06,
+			Int07:  w.Int07,
+			Int08:  w.Int08,
+			Int09:  w.Int09,
+			Int10:  w.Int10,
+			Int11:  w.Int11,
+			Int12:  w.Int12,
+			Num01:  w.Num01,
+			Num02:  w.Num02,
+			Num03:  w.Num03,
+			Num04:  w.Num04,
+			Num05:  w.Num05,
+			Num06:  w.Num06,
+			Num07:  w.Num07,
+			Num08:  w.Num08,
+			Num09:  w.Num09,
+			Num10:  w.Num10,
+			Num11:  w.Num11,
+			Num12:  w.Num12,
+			Time01: w.Time01,
+			Time02: w.Time02,
+			Time03: w.Time03,
+			Time04: w.Time04,
+			Time05: w.Time05,
+			Time06: w.Time06,
+			Time07: w.Time07,
+			Time08: w.Time08,
+			Time09: w.Time09,
+			Time10: w.Time10,
+			Time11: w.Time11,
+			Time12: w.Time12,
+			Tags01: w.Tags01,
+			Tags02: w.Tags02,
+			Tags03: w.Tags03,
+			Tags04: w.Tags04,
+			Tags05: w.Tags05,
+			Tags06: w.Tags06,
+			Tags07: w.Tags07,
+			Tags08: w.Tags08,
+			Tags09: w.Tags09,
+			Tags10: w.Tags10,
+			Tags11: w.Tags11,
+			Tags12: w.Tags12,
+			Note01: w.Note01,
+			Note02: w.Note02,
+			Note03: w.Note03,
+			Note04: w.Note04,
+			Note05: w.Note05,
+			Note06: w.Note06,
+			Note07: w.Note07,
+			Note08: w.Note08,
+			Note09: w.Note09,
+			Note10: w.Note10,
+			Note11: w.Note11,
+			Note12: w.Note12,
+		}
+	}
+
+	return wide, nil
+}
+
+func (r Repository) QueryDirectors(ctx context.Context, params benchflix.PreloadParams) ([]benchflix.MovieDirectors, error) {
+	var (
+		rows  []MovieDirectors
+		query string
+	)
+
+	switch params.Strategy {
+	case benchflix.PreloadAny:
+		query = `
+			SELECT md.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+			FROM movie_directors md
+			JOIN people ON people.id = md.person_id
+			WHERE md.movie_id = ANY (CAST(@ids AS INT8[]))
+			GROUP BY md.movie_id;
+		`
+	case benchflix.PreloadUnnest:
+		query = `
+			SELECT i.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+			FROM unnest(CAST(@ids AS INT8[])) WITH ORDINALITY AS i (movie_id, ord)
+			JOIN movie_directors md ON md.movie_id = i.movie_id
+			JOIN people ON people.id = md.person_id
+			GROUP BY i.ord, i.movie_id
+			ORDER BY i.ord;
+		`
+	case benchflix.PreloadTempTable, benchflix.PreloadBatch:
+		return nil, benchflix.ErrSkip
+	default:
+		return nil, fmt.Errorf("invalid strategy: %s", params.Strategy)
+	}
+
+	if err := r.DB.WithContext(ctx).Raw(query, sql.Named("ids", pq.Int64Array(params.IDs))).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	movieDirectors := make([]benchflix.MovieDirectors, len(rows))
+
+	for i, md := range rows {
+		movieDirectors[i] = benchflix.MovieDirectors{
+			MovieID:   md.MovieID,
+			Directors: md.Directors,
+		}
+	}
+
+	return movieDirectors, nil
+}
+
+func (r Repository) WithLoader(wait time.Duration, maxBatch int) benchflix.Repository {
+	r.Loader = benchflix.NewDirectorsLoader(wait, maxBatch, r.QueryDirectors)
+
+	return r
+}
+
+func (r Repository) loadMovieDirectors(ctx context.Context, movies []benchflix.Movie) ([]benchflix.Movie, error) {
+	ids := make([]int64, len(movies))
+
+	for i, m := range movies {
+		ids[i] = m.ID
+	}
+
+	directors, err := r.Loader.LoadMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, m := range movies {
+		movies[i].Directors = directors[m.ID]
+	}
+
+	return movies, nil
+}
+
+func (r Repository) PoolStats() benchflix.PoolStats {
+	return benchflix.SQLPoolStats(benchflix.Must(r.DB.DB()))
+}
+
+func (r Repository) Close() error {
+	db, err := r.DB.DB()
+	if err != nil {
+		return err
+	}
+
+	return db.Close()
+}
@@ -0,0 +1,152 @@
+package benchflix
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Loadable is implemented by repositories whose *Preload scenarios can resolve
+// directors through a Loader instead of issuing their own lookup.
+type Loadable interface {
+	WithLoader(wait time.Duration, maxBatch int) Repository
+}
+
+// Loader coalesces concurrent lookups into batched fetches. Keys requested
+// within wait of the first pending key share one fetch; a batch reaching
+// maxBatch keys is dispatched immediately. A maxBatch <= 0 means no limit.
+type Loader[K comparable, V any] struct {
+	fetch    func(ctx context.Context, keys []K) (map[K]V, error)
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	batch *loaderBatch[K, V]
+}
+
+type loaderBatch[K comparable, V any] struct {
+	ctx    context.Context
+	timer  *time.Timer
+	keys   []K
+	seen   map[K]struct{}
+	done   chan struct{}
+	values map[K]V
+	err    error
+}
+
+// NewDirectorsLoader returns a loader that resolves the directors of movies
+// through the PreloadAny strategy of query.
+func NewDirectorsLoader(wait time.Duration, maxBatch int, query func(context.Context, PreloadParams) ([]MovieDirectors, error)) *Loader[int64, []string] {
+	return NewLoader(wait, maxBatch, func(ctx context.Context, ids []int64) (map[int64][]string, error) {
+		movieDirectors, err := query(ctx, PreloadParams{Strategy: PreloadAny, IDs: ids})
+		if err != nil {
+			return nil, err
+		}
+
+		directors := make(map[int64][]string, len(movieDirectors))
+
+		for _, md := range movieDirectors {
+			directors[md.MovieID] = md.Directors
+		}
+
+		return directors, nil
+	})
+}
+
+func NewLoader[K comparable, V any](wait time.Duration, maxBatch int, fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+	}
+}
+
+// LoadMany returns the values of all keys that the fetch function resolved.
+// Keys without a value are missing from the result.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) (map[K]V, error) {
+	var batches []*loaderBatch[K, V]
+
+	l.mu.Lock()
+
+	for _, key := range keys {
+		b := l.batch
+
+		if b == nil {
+			b = &loaderBatch[K, V]{
+				ctx:  context.WithoutCancel(ctx),
+				seen: make(map[K]struct{}),
+				done: make(chan struct{}),
+			}
+
+			b.timer = time.AfterFunc(l.wait, func() { l.dispatch(b) })
+			l.batch = b
+		}
+
+		if len(batches) == 0 || batches[len(batches)-1] != b {
+			batches = append(batches, b)
+		}
+
+		if _, ok := b.seen[key]; ok {
+			continue
+		}
+
+		b.seen[key] = struct{}{}
+		b.keys = append(b.keys, key)
+
+		if l.maxBatch > 0 && len(b.keys) >= l.maxBatch {
+			b.timer.Stop()
+			l.batch = nil
+
+			go l.run(b)
+		}
+	}
+
+	l.mu.Unlock()
+
+	result := make(map[K]V, len(keys))
+
+	for _, b := range batches {
+		select {
+		case <-b.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if b.err != nil {
+			return nil, b.err
+		}
+	}
+
+	for _, key := range keys {
+		for _, b := range batches {
+			if v, ok := b.values[key]; ok {
+				result[key] = v
+
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (l *Loader[K, V]) dispatch(b *loaderBatch[K, V]) {
+	l.mu.Lock()
+
+	if l.batch != b {
+		l.mu.Unlock()
+
+		return
+	}
+
+	l.batch = nil
+	l.mu.Unlock()
+
+	l.run(b)
+}
+
+func (l *Loader[K, V]) run(b *loaderBatch[K, V]) {
+	b.values, b.err = l.fetch(b.ctx, b.keys)
+
+	close(b.done)
+}
@@ -0,0 +1,36 @@
+package benchflix
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Logging logs every repository call with its params, result size, duration
+// and Status. Failed calls are logged at error level.
+func Logging(logger *slog.Logger) Middleware {
+	return Intercept(func(ctx context.Context, call Call, next func(context.Context) (any, error)) (any, error) {
+		start := time.Now()
+
+		result, err := next(ctx)
+
+		attrs := []slog.Attr{
+			slog.String("method", call.Method),
+			slog.Any("params", call.Params),
+			slog.Int("results", ResultSize(result)),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("status", Status(err)),
+		}
+
+		level := slog.LevelInfo
+
+		if Status(err) == "error" {
+			level = slog.LevelError
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+
+		logger.LogAttrs(ctx, level, "repository call", attrs...)
+
+		return result, err
+	})
+}
@@ -0,0 +1,228 @@
+package benchflix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Matrix declares a benchmark run: which frameworks run which szenarios with
+// how many params, connection pools and repetitions. Empty fields keep the
+// defaults of the harness.
+type Matrix struct {
+	Frameworks   []MatrixFramework `json:"frameworks"`
+	Szenarios    []string          `json:"szenarios"`
+	Sizes        []int             `json:"sizes"`
+	PreloadSizes []int             `json:"preload_sizes"`
+	CacheSizes   []int             `json:"cache_sizes"`
+	Pools        []MatrixPool      `json:"pools"`
+	IdleTimeout  Duration          `json:"idle_timeout"`
+	Count        int               `json:"count"`
+	Timeout      Duration          `json:"timeout"`
+	Warmup       string            `json:"warmup"`
+	Settle       Duration          `json:"settle"`
+}
+
+// MatrixPool is the size of a connection pool. Every pool is a sub-benchmark
+// of the frameworks named pool=MIN-MAX, which ReadAll keeps as a label.
+type MatrixPool struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+func (p MatrixPool) String() string {
+	return fmt.Sprintf("%d-%d", p.Min, p.Max)
+}
+
+// MatrixFramework is a framework with its options. Name is the name of the
+// sub-benchmark and defaults to the upper case adapter.
+type MatrixFramework struct {
+	Name    string `json:"name"`
+	Adapter string `json:"adapter"`
+	// ExpressionSize sets sqlt.ExpressionSize.
+	ExpressionSize int `json:"expression_size,omitempty"`
+}
+
+// Adapter opens a repository of one adapter package.
+type Adapter func(conn string, min, max int, idle time.Duration, opts ...Option) (Repository, error)
+
+var adapters = map[string]Adapter{}
+
+// RegisterAdapter makes an adapter available as MatrixFramework.Adapter. The
+// adapter packages register themselves when they are imported.
+func RegisterAdapter(name string, adapter Adapter) {
+	adapters[name] = adapter
+}
+
+// Adapters returns the names of the registered adapters.
+func Adapters() []string {
+	return slices.Sorted(maps.Keys(adapters))
+}
+
+// DefaultFrameworks are benchmarked in this order unless a matrix declares
+// others. matrix.json lists the same.
+var DefaultFrameworks = []MatrixFramework{
+	{Name: "SQL", Adapter: "sql"},
+	{Name: "PGX", Adapter: "pgx"},
+	{Name: "SQUIRREL", Adapter: "squirrel"},
+	{Name: "SQLX", Adapter: "sqlx"},
+	{Name: "GORM", Adapter: "gorm"},
+	{Name: "SQLC", Adapter: "sqlc"},
+	{Name: "SQLT", Adapter: "sqlt"},
+	{Name: "SQLT-Cache", Adapter: "sqlt", ExpressionSize: 10_000},
+}
+
+// Szenarios are the names of the sub-benchmarks a matrix can select.
+var Szenarios = []string{
+	"List", "ListPreload", "ListPreloadCached", "ListPreloadLoader", "ListNPlusOne", "ListJSON",
+	"Dashboard", "DashboardPreload", "DashboardPreloadCached", "DashboardPreloadLoader",
+	"Details", "DetailsNull", "Search", "Websearch", "Facets", "FacetsBatch", "TopRated", "Collaborators", "Movie", "Wide",
+	"PreloadAny", "PreloadUnnest", "PreloadTempTable", "PreloadBatch",
+}
+
+// Open opens the repository of f with its options.
+func (f MatrixFramework) Open(conn string, min, max int, idle time.Duration, opts ...Option) (Repository, error) {
+	adapter, ok := adapters[f.Adapter]
+	if !ok {
+		return nil, fmt.Errorf("%s: invalid adapter %q, want one of %s", f.Name, f.Adapter, strings.Join(Adapters(), ", "))
+	}
+
+	if f.ExpressionSize > 0 {
+		opts = append(opts, WithExpressionSize(f.ExpressionSize))
+	}
+
+	return adapter(conn, min, max, idle, opts...)
+}
+
+// Duration is a time.Duration written as "2m" in JSON.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func LoadMatrix(path string) (Matrix, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Matrix{}, err
+	}
+
+	var m Matrix
+
+	if err = json.Unmarshal(data, &m); err != nil {
+		return Matrix{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err = m.validate(); err != nil {
+		return Matrix{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return m, nil
+}
+
+func (m *Matrix) validate() error {
+	var errs []error
+
+	names := map[string]bool{}
+
+	for i, f := range m.Frameworks {
+		if _, ok := adapters[f.Adapter]; !ok {
+			errs = append(errs, fmt.Errorf("frameworks[%d]: invalid adapter %q, want one of %s", i, f.Adapter, strings.Join(Adapters(), ", ")))
+		}
+
+		if f.ExpressionSize != 0 && f.Adapter != "sqlt" {
+			errs = append(errs, fmt.Errorf("frameworks[%d]: expression_size is only supported by sqlt", i))
+		}
+
+		if f.Name == "" {
+			m.Frameworks[i].Name = strings.ToUpper(f.Adapter)
+		}
+
+		if names[m.Frameworks[i].Name] {
+			errs = append(errs, fmt.Errorf("frameworks[%d]: duplicate name %s", i, m.Frameworks[i].Name))
+		}
+
+		names[m.Frameworks[i].Name] = true
+	}
+
+	for i, name := range m.Szenarios {
+		if !slices.Contains(Szenarios, name) {
+			errs = append(errs, fmt.Errorf("szenarios[%d]: invalid szenario %q, want one of %s", i, name, strings.Join(Szenarios, ", ")))
+		}
+	}
+
+	for _, sizes := range [][]int{m.Sizes, m.PreloadSizes, m.CacheSizes} {
+		for _, size := range sizes {
+			if size <= 0 {
+				errs = append(errs, fmt.Errorf("invalid size: %d", size))
+			}
+		}
+	}
+
+	pools := map[MatrixPool]bool{}
+
+	for i, p := range m.Pools {
+		if p.Min < 0 || p.Max <= 0 || p.Min > p.Max {
+			errs = append(errs, fmt.Errorf("pools[%d]: invalid connections: min %d, max %d", i, p.Min, p.Max))
+		}
+
+		if pools[p] {
+			errs = append(errs, fmt.Errorf("pools[%d]: duplicate pool %s", i, p))
+		}
+
+		pools[p] = true
+	}
+
+	for _, d := range []struct {
+		Name  string
+		Value Duration
+	}{
+		{"idle_timeout", m.IdleTimeout},
+		{"timeout", m.Timeout},
+		{"settle", m.Settle},
+	} {
+		if d.Value < 0 {
+			errs = append(errs, fmt.Errorf("invalid %s: %s", d.Name, time.Duration(d.Value)))
+		}
+	}
+
+	if m.Count < 0 {
+		errs = append(errs, fmt.Errorf("invalid count: %d", m.Count))
+	}
+
+	if m.Warmup != "" {
+		if _, err := ParseWarmup(m.Warmup); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// HasSzenario reports whether the matrix runs the szenario, which is the case
+// for every szenario if none are listed.
+func (m Matrix) HasSzenario(name string) bool {
+	return len(m.Szenarios) == 0 || slices.Contains(m.Szenarios, name)
+}
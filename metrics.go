@@ -0,0 +1,148 @@
+package benchflix
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Buckets are the upper bounds in seconds of the latency histogram, matching the
+// Prometheus client defaults.
+var Buckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics counts repository calls per method and Status and records their
+// latency. It is an http.Handler serving the Prometheus text exposition format,
+// benchflix run -metrics serves it for scraping.
+type Metrics struct {
+	methods sync.Map
+}
+
+type methodMetrics struct {
+	ok, skipped, failed atomic.Int64
+	buckets             []atomic.Int64
+	sum                 atomic.Int64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Middleware records every call of the wrapped repository.
+func (m *Metrics) Middleware(repo Repository) Repository {
+	return Intercept(func(ctx context.Context, call Call, next func(context.Context) (any, error)) (any, error) {
+		start := time.Now()
+
+		result, err := next(ctx)
+
+		m.observe(call.Method, time.Since(start), err)
+
+		return result, err
+	})(repo)
+}
+
+func (m *Metrics) observe(method string, duration time.Duration, err error) {
+	value, ok := m.methods.Load(method)
+	if !ok {
+		value, _ = m.methods.LoadOrStore(method, &methodMetrics{
+			buckets: make([]atomic.Int64, len(Buckets)+1),
+		})
+	}
+
+	mm := value.(*methodMetrics)
+
+	switch Status(err) {
+	case "ok":
+		mm.ok.Add(1)
+	case "skipped":
+		mm.skipped.Add(1)
+	default:
+		mm.failed.Add(1)
+	}
+
+	seconds := duration.Seconds()
+	bucket, _ := slices.BinarySearch(Buckets, seconds)
+
+	mm.buckets[bucket].Add(1)
+	mm.sum.Add(int64(duration))
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	_ = m.WriteText(w)
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format.
+func (m *Metrics) WriteText(w io.Writer) error {
+	var methods []string
+
+	m.methods.Range(func(key, _ any) bool {
+		methods = append(methods, key.(string))
+
+		return true
+	})
+
+	slices.Sort(methods)
+
+	load := func(method string) *methodMetrics {
+		value, _ := m.methods.Load(method)
+
+		return value.(*methodMetrics)
+	}
+
+	if _, err := fmt.Fprint(w, "# HELP benchflix_repository_calls_total Repository calls by method and status.\n"+
+		"# TYPE benchflix_repository_calls_total counter\n"); err != nil {
+		return err
+	}
+
+	for _, method := range methods {
+		mm := load(method)
+
+		if _, err := fmt.Fprintf(w, "benchflix_repository_calls_total{method=%q,status=\"ok\"} %d\n"+
+			"benchflix_repository_calls_total{method=%q,status=\"skipped\"} %d\n"+
+			"benchflix_repository_calls_total{method=%q,status=\"error\"} %d\n",
+			method, mm.ok.Load(), method, mm.skipped.Load(), method, mm.failed.Load()); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprint(w, "# HELP benchflix_repository_duration_seconds Repository call latency by method.\n"+
+		"# TYPE benchflix_repository_duration_seconds histogram\n"); err != nil {
+		return err
+	}
+
+	for _, method := range methods {
+		var (
+			mm    = load(method)
+			count int64
+		)
+
+		for i := range mm.buckets {
+			count += mm.buckets[i].Load()
+
+			le := "+Inf"
+
+			if i < len(Buckets) {
+				le = strconv.FormatFloat(Buckets[i], 'g', -1, 64)
+			}
+
+			if _, err := fmt.Fprintf(w, "benchflix_repository_duration_seconds_bucket{method=%q,le=%q} %d\n", method, le, count); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "benchflix_repository_duration_seconds_sum{method=%q} %g\n"+
+			"benchflix_repository_duration_seconds_count{method=%q} %d\n",
+			method, time.Duration(mm.sum.Load()).Seconds(), method, count); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package benchflix
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// Middleware decorates a Repository, e.g. with metrics, logging or tracing.
+type Middleware func(Repository) Repository
+
+// Chain wraps repo with the middlewares. The first middleware is the outermost
+// and sees every call first.
+func Chain(repo Repository, middlewares ...Middleware) Repository {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		repo = middlewares[i](repo)
+	}
+
+	return repo
+}
+
+// Call describes a single repository call as seen by an Interceptor.
+type Call struct {
+	Method string
+	Params any
+}
+
+// Interceptor observes a repository call. It must call next exactly once and
+// return its result.
+type Interceptor func(ctx context.Context, call Call, next func(context.Context) (any, error)) (any, error)
+
+// Intercept turns an Interceptor into a Middleware that runs it around every
+// repository method. The wrapped repository is Pooled, Loadable or an io.Closer
+// if repo is; a loader repository is intercepted as well.
+func Intercept(fn Interceptor) Middleware {
+	return func(repo Repository) Repository {
+		var (
+			r                  = interceptedRepository{next: repo, fn: fn}
+			pooled, isPooled   = repo.(Pooled)
+			loadable, isLoader = repo.(Loadable)
+			closer, isCloser   = repo.(io.Closer)
+			p                  = pooledRepository{pooled: pooled}
+			l                  = loadableRepository{loadable: loadable, fn: fn}
+			c                  = closerRepository{closer: closer}
+		)
+
+		switch {
+		case isPooled && isLoader && isCloser:
+			return struct {
+				interceptedRepository
+				pooledRepository
+				loadableRepository
+				closerRepository
+			}{r, p, l, c}
+		case isPooled && isLoader:
+			return struct {
+				interceptedRepository
+				pooledRepository
+				loadableRepository
+			}{r, p, l}
+		case isPooled && isCloser:
+			return struct {
+				interceptedRepository
+				pooledRepository
+				closerRepository
+			}{r, p, c}
+		case isLoader && isCloser:
+			return struct {
+				interceptedRepository
+				loadableRepository
+				closerRepository
+			}{r, l, c}
+		case isPooled:
+			return struct {
+				interceptedRepository
+				pooledRepository
+			}{r, p}
+		case isLoader:
+			return struct {
+				interceptedRepository
+				loadableRepository
+			}{r, l}
+		case isCloser:
+			return struct {
+				interceptedRepository
+				closerRepository
+			}{r, c}
+		default:
+			return r
+		}
+	}
+}
+
+// Status classifies the outcome of a call as "ok", "skipped" for ErrSkip or
+// "error", so that all middlewares report unsupported szenarios alike.
+func Status(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, ErrSkip):
+		return "skipped"
+	default:
+		return "error"
+	}
+}
+
+type pooledRepository struct {
+	pooled Pooled
+}
+
+func (r pooledRepository) PoolStats() PoolStats {
+	return r.pooled.PoolStats()
+}
+
+type loadableRepository struct {
+	loadable Loadable
+	fn       Interceptor
+}
+
+func (r loadableRepository) WithLoader(wait time.Duration, maxBatch int) Repository {
+	return Intercept(r.fn)(r.loadable.WithLoader(wait, maxBatch))
+}
+
+type closerRepository struct {
+	closer io.Closer
+}
+
+func (r closerRepository) Close() error {
+	return r.closer.Close()
+}
+
+type interceptedRepository struct {
+	next Repository
+	fn   Interceptor
+}
+
+func intercept[P, R any](ctx context.Context, r interceptedRepository, method string, params P, query func(context.Context, P) (R, error)) (R, error) {
+	result, err := r.fn(ctx, Call{Method: method, Params: params}, func(ctx context.Context) (any, error) {
+		return query(ctx, params)
+	})
+
+	value, _ := result.(R)
+
+	return value, err
+}
+
+func (r interceptedRepository) QueryList(ctx context.Context, params ListParams) ([]Movie, error) {
+	return intercept(ctx, r, "QueryList", params, r.next.QueryList)
+}
+
+func (r interceptedRepository) QueryListPreload(ctx context.Context, params ListParams) ([]Movie, error) {
+	return intercept(ctx, r, "QueryListPreload", params, r.next.QueryListPreload)
+}
+
+func (r interceptedRepository) QueryListNPlusOne(ctx context.Context, params ListParams) ([]Movie, error) {
+	return intercept(ctx, r, "QueryListNPlusOne", params, r.next.QueryListNPlusOne)
+}
+
+func (r interceptedRepository) QueryListJSON(ctx context.Context, params ListParams) ([]Movie, error) {
+	return intercept(ctx, r, "QueryListJSON", params, r.next.QueryListJSON)
+}
+
+func (r interceptedRepository) QueryDashboard(ctx context.Context, params DashboardParams) ([]Movie, error) {
+	return intercept(ctx, r, "QueryDashboard", params, r.next.QueryDashboard)
+}
+
+func (r interceptedRepository) QueryDashboardPreload(ctx context.Context, params DashboardParams) ([]Movie, error) {
+	return intercept(ctx, r, "QueryDashboardPreload", params, r.next.QueryDashboardPreload)
+}
+
+func (r interceptedRepository) QueryDetails(ctx context.Context, params ListParams) ([]Movie, error) {
+	return intercept(ctx, r, "QueryDetails", params, r.next.QueryDetails)
+}
+
+func (r interceptedRepository) QueryDetailsNull(ctx context.Context, params ListParams) ([]Movie, error) {
+	return intercept(ctx, r, "QueryDetailsNull", params, r.next.QueryDetailsNull)
+}
+
+func (r interceptedRepository) QuerySearch(ctx context.Context, params SearchParams) ([]SearchResult, error) {
+	return intercept(ctx, r, "QuerySearch", params, r.next.QuerySearch)
+}
+
+func (r interceptedRepository) QueryFacets(ctx context.Context, params DashboardParams) (FacetResult, error) {
+	return intercept(ctx, r, "QueryFacets", params, r.next.QueryFacets)
+}
+
+func (r interceptedRepository) QueryFacetsBatch(ctx context.Context, params DashboardParams) (FacetResult, error) {
+	return intercept(ctx, r, "QueryFacetsBatch", params, r.next.QueryFacetsBatch)
+}
+
+func (r interceptedRepository) QueryTopRated(ctx context.Context, params TopParams) ([]TopMovie, error) {
+	return intercept(ctx, r, "QueryTopRated", params, r.next.QueryTopRated)
+}
+
+func (r interceptedRepository) QueryCollaborators(ctx context.Context, params GraphParams) ([]Collaborator, error) {
+	return intercept(ctx, r, "QueryCollaborators", params, r.next.QueryCollaborators)
+}
+
+func (r interceptedRepository) QueryMovie(ctx context.Context, id int64) (Movie, error) {
+	return intercept(ctx, r, "QueryMovie", id, r.next.QueryMovie)
+}
+
+func (r interceptedRepository) QueryWide(ctx context.Context, params WideParams) ([]WideRow, error) {
+	return intercept(ctx, r, "QueryWide", params, r.next.QueryWide)
+}
+
+func (r interceptedRepository) QueryDirectors(ctx context.Context, params PreloadParams) ([]MovieDirectors, error) {
+	return intercept(ctx, r, "QueryDirectors", params, r.next.QueryDirectors)
+}
+
+// ResultSize returns the number of rows in a repository result.
+func ResultSize(result any) int {
+	switch r := result.(type) {
+	case []Movie:
+		return len(r)
+	case []SearchResult:
+		return len(r)
+	case FacetResult:
+		return len(r.Movies)
+	case []TopMovie:
+		return len(r)
+	case []Collaborator:
+		return len(r)
+	case Movie:
+		return 1
+	case []WideRow:
+		return len(r)
+	case []MovieDirectors:
+		return len(r)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,37 @@
+package benchflix
+
+// Option configures NewRepository of an adapter.
+type Option func(*Options)
+
+// Options are built from the Option values passed to NewRepository.
+type Options struct {
+	// Instrument opens the connections with InstrumentPgx or PhaseConnector.
+	Instrument bool
+	// ExpressionSize sets sqlt.ExpressionSize, other adapters ignore it.
+	ExpressionSize int
+}
+
+func NewOptions(opts ...Option) Options {
+	var o Options
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithInstrumentation makes a repository report to MeasurePhases and
+// CaptureStatements. The tracer and driver wrappers add overhead to every call,
+// so benchmarks only use it with -phases.
+func WithInstrumentation() Option {
+	return func(o *Options) {
+		o.Instrument = true
+	}
+}
+
+func WithExpressionSize(size int) Option {
+	return func(o *Options) {
+		o.ExpressionSize = size
+	}
+}
@@ -0,0 +1,74 @@
+package benchflix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ParamSet holds the params of every szenario. Only list, dashboard and search
+// params are generated, the others are derived from them and the movies.
+type ParamSet struct {
+	List      []ListParams
+	Dashboard []DashboardParams
+	Search    []SearchParams
+	Websearch []SearchParams
+	Top       []TopParams
+	Graph     []GraphParams
+	MovieIDs  []int64
+	Wide      []WideParams
+}
+
+// LoadParamSet reads a params file generated by benchflix params.
+func LoadParamSet(path string) (ParamSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ParamSet{}, err
+	}
+
+	var p ParamSet
+
+	if err = errors.Join(json.Unmarshal(data, &p.List), json.Unmarshal(data, &p.Dashboard), json.Unmarshal(data, &p.Search)); err != nil {
+		return ParamSet{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if len(p.Dashboard) == 0 {
+		return ParamSet{}, fmt.Errorf("%s: no params", path)
+	}
+
+	var directors []string
+
+	for _, m := range Movies {
+		for _, d := range m.Directors {
+			if d != "" {
+				directors = append(directors, d)
+			}
+		}
+	}
+
+	if len(directors) == 0 {
+		return ParamSet{}, errors.New("no directors in the movies")
+	}
+
+	p.Websearch = make([]SearchParams, len(p.Search))
+
+	for i, s := range p.Search {
+		s.Websearch = true
+		p.Websearch[i] = s
+	}
+
+	p.Top = make([]TopParams, len(p.Dashboard))
+	p.Graph = make([]GraphParams, len(p.Dashboard))
+	p.MovieIDs = make([]int64, len(p.Dashboard))
+	p.Wide = make([]WideParams, len(p.Dashboard))
+
+	for i, d := range p.Dashboard {
+		p.Top[i] = TopParams{MinRating: d.MinRating, PerYear: 1 + d.Limit%10}
+		p.Graph[i] = GraphParams{Director: directors[i%len(directors)], Depth: 1 + d.Limit%3}
+		p.MovieIDs[i] = Movies[i%len(Movies)].ID
+		p.Wide[i] = WideParams{After: int64(i), Limit: 1000}
+	}
+
+	return p, nil
+}
@@ -2,31 +2,53 @@ package pgxflix
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/go-sqlt/benchflix"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewRepository(conn string, min, max int, idle time.Duration) benchflix.Repository {
-	cfg := benchflix.Must(pgxpool.ParseConfig(conn))
+func init() {
+	benchflix.RegisterAdapter("pgx", Open)
+}
+
+func NewRepository(conn string, min, max int, idle time.Duration, opts ...benchflix.Option) benchflix.Repository {
+	return benchflix.Must(Open(conn, min, max, idle, opts...))
+}
+
+// Open is NewRepository returning errors.
+func Open(conn string, min, max int, idle time.Duration, opts ...benchflix.Option) (benchflix.Repository, error) {
+	cfg, err := pgxpool.ParseConfig(conn)
+	if err != nil {
+		return nil, err
+	}
 
 	cfg.MaxConns = int32(max)
 	cfg.MinConns = int32(min)
 	cfg.MaxConnIdleTime = idle
 
-	pool := benchflix.Must(pgxpool.NewWithConfig(context.Background(), cfg))
+	if benchflix.NewOptions(opts...).Instrument {
+		benchflix.InstrumentPgx(cfg.ConnConfig)
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	return Repository{
 		Pool: pool,
-	}
+	}, nil
 }
 
 type Repository struct {
-	Pool *pgxpool.Pool
+	Pool   *pgxpool.Pool
+	Loader *benchflix.Loader[int64, []string]
 }
 
 func (r Repository) QueryList(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
@@ -133,6 +155,19 @@ func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListP
 		return movies, nil
 	}
 
+	if r.Loader != nil {
+		directors, err := r.Loader.LoadMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, d := range directors {
+			movies[idMap[id]].Directors = d
+		}
+
+		return movies, nil
+	}
+
 	dirRows, err := r.Pool.Query(ctx, `
 		SELECT
 			md.movie_id
@@ -168,6 +203,128 @@ func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListP
 	return movies, nil
 }
 
+func (r Repository) QueryListNPlusOne(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+		FROM movies m
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3 = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`, params.Search, params.YearAdded, params.MinRating, params.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	movies, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (benchflix.Movie, error) {
+		var movie benchflix.Movie
+
+		return movie, row.Scan(&movie.ID, &movie.Title, &movie.AddedAt, &movie.Rating)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range movies {
+		dirRows, err := r.Pool.Query(ctx, `
+			SELECT people.name
+			FROM movie_directors md
+			JOIN people ON people.id = md.person_id
+			WHERE md.movie_id = $1
+			ORDER BY people.name;
+		`, movies[i].ID)
+		if err != nil {
+			return nil, err
+		}
+
+		directors, err := pgx.CollectRows(dirRows, pgx.RowTo[string])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(directors) > 0 {
+			movies[i].Directors = directors
+		}
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QueryListJSON(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, COALESCE(d.directors, '[]') AS directors
+		FROM movies m
+		LEFT JOIN LATERAL (
+			SELECT json_agg(json_build_object('id', p.id, 'name', p.name) ORDER BY p.name) AS directors
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+		) d ON true
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3 = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`, params.Search, params.YearAdded, params.MinRating, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (benchflix.Movie, error) {
+		var (
+			m      benchflix.Movie
+			people []benchflix.Person
+		)
+
+		if err := row.Scan(&m.ID, &m.Title, &m.AddedAt, &m.Rating, &people); err != nil {
+			return m, err
+		}
+
+		if len(people) > 0 {
+			m.Directors = make([]string, len(people))
+
+			for i, p := range people {
+				m.Directors[i] = p.Name
+			}
+		}
+
+		return m, nil
+	})
+}
+
 func (r Repository) QueryDashboard(ctx context.Context, params benchflix.DashboardParams) ([]benchflix.Movie, error) {
 	sb := &strings.Builder{}
 
@@ -341,6 +498,19 @@ func (r Repository) QueryDashboardPreload(ctx context.Context, params benchflix.
 		return movies, nil
 	}
 
+	if r.Loader != nil {
+		directors, err := r.Loader.LoadMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, d := range directors {
+			movies[idMap[id]].Directors = d
+		}
+
+		return movies, nil
+	}
+
 	dirRows, err := r.Pool.Query(ctx, `
 		SELECT
 			md.movie_id
@@ -375,3 +545,802 @@ func (r Repository) QueryDashboardPreload(ctx context.Context, params benchflix.
 
 	return movies, nil
 }
+
+func (r Repository) QueryDetails(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, m.runtime
+			, m.budget
+			, m.tagline
+			, m.metadata
+		FROM movies m
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3 = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`, params.Search, params.YearAdded, params.MinRating, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (benchflix.Movie, error) {
+		var m benchflix.Movie
+
+		if err := row.Scan(&m.ID, &m.Title, &m.AddedAt, &m.Rating, &m.Runtime, &m.Budget, &m.Tagline, &m.Metadata); err != nil {
+			return m, err
+		}
+
+		return m, nil
+	})
+}
+
+func (r Repository) QueryDetailsNull(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, m.runtime
+			, m.budget
+			, m.tagline
+			, m.metadata
+		FROM movies m
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3 = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`, params.Search, params.YearAdded, params.MinRating, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (benchflix.Movie, error) {
+		var (
+			m       benchflix.Movie
+			runtime pgtype.Int8
+			budget  pgtype.Int8
+			tagline pgtype.Text
+		)
+
+		if err := row.Scan(&m.ID, &m.Title, &m.AddedAt, &m.Rating, &runtime, &budget, &tagline, &m.Metadata); err != nil {
+			return m, err
+		}
+
+		if runtime.Valid {
+			m.Runtime = &runtime.Int64
+		}
+
+		if budget.Valid {
+			m.Budget = &budget.Int64
+		}
+
+		if tagline.Valid {
+			m.Tagline = &tagline.String
+		}
+
+		return m, nil
+	})
+}
+
+func (r Repository) QuerySearch(ctx context.Context, params benchflix.SearchParams) ([]benchflix.SearchResult, error) {
+	tsquery := "plainto_tsquery"
+
+	if params.Websearch {
+		tsquery = "websearch_to_tsquery"
+	}
+
+	rows, err := r.Pool.Query(ctx, fmt.Sprintf(`
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, d.directors
+			, ts_rank(
+				setweight(to_tsvector('simple', m.title), 'A')
+				|| setweight(to_tsvector('simple', COALESCE(d.names, '')), 'B'),
+				q.query
+			) AS rank
+			, ts_headline('simple', m.title || ' / ' || COALESCE(d.names, ''), q.query) AS headline
+		FROM movies m
+		CROSS JOIN %s('simple', $1) AS q (query)
+		LEFT JOIN LATERAL (
+			SELECT
+				ARRAY_AGG(p.name ORDER BY p.name) AS directors
+				, STRING_AGG(p.name, ' ' ORDER BY p.name) AS names
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+		) d ON true
+		WHERE
+			to_tsvector('simple', m.title) @@ q.query
+			OR EXISTS (
+				SELECT 1
+				FROM movie_directors md
+				JOIN people p ON p.id = md.person_id
+				WHERE md.movie_id = m.id
+				AND to_tsvector('simple', p.name) @@ q.query
+			)
+		ORDER BY rank DESC, m.id
+		LIMIT CASE WHEN $2 BETWEEN 1 AND 1000 THEN $2 ELSE 1000 END;
+	`, tsquery), params.Search, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (benchflix.SearchResult, error) {
+		var s benchflix.SearchResult
+
+		if err := row.Scan(&s.ID, &s.Title, &s.AddedAt, &s.Rating, &s.Directors, &s.Rank, &s.Headline); err != nil {
+			return s, err
+		}
+
+		return s, nil
+	})
+}
+
+func (r Repository) QueryFacets(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	var (
+		result benchflix.FacetResult
+		sb     = &strings.Builder{}
+		err    error
+	)
+
+	result.Movies, err = r.QueryDashboardPreload(ctx, params)
+	if err != nil {
+		return result, err
+	}
+
+	sb.WriteString(`
+		SELECT
+			CASE WHEN GROUPING(EXTRACT(YEAR FROM m.added_at)) = 0 THEN 'year' ELSE 'rating' END AS facet
+			, CAST(COALESCE(EXTRACT(YEAR FROM m.added_at), FLOOR(m.rating)) AS INT8) AS value
+			, COUNT(*) AS count
+		FROM movies m
+		WHERE 1=1`)
+
+	if params.Search != "" {
+		sb.WriteString(` AND (
+			to_tsvector('simple', m.title) @@ plainto_tsquery('simple', @search)
+			OR EXISTS (
+			SELECT 1 FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+				AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', @search)
+			)
+		)`)
+	}
+	if params.YearAdded != 0 {
+		sb.WriteString(" AND EXTRACT(YEAR FROM m.added_at) = @year_added")
+	}
+	if params.MinRating != 0 {
+		sb.WriteString(" AND m.rating >= @min_rating")
+	}
+
+	sb.WriteString(" GROUP BY GROUPING SETS ((EXTRACT(YEAR FROM m.added_at)), (FLOOR(m.rating))) ORDER BY facet, value")
+
+	rows, err := r.Pool.Query(ctx, sb.String(), pgx.NamedArgs{
+		"search":     params.Search,
+		"year_added": params.YearAdded,
+		"min_rating": params.MinRating,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			facet string
+			f     benchflix.Facet
+		)
+
+		if err := rows.Scan(&facet, &f.Value, &f.Count); err != nil {
+			return result, err
+		}
+
+		if facet == "year" {
+			result.Years = append(result.Years, f)
+		} else {
+			result.Ratings = append(result.Ratings, f)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (r Repository) QueryFacetsBatch(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	var (
+		result benchflix.FacetResult
+		index  = 0
+		ids    = make([]int64, 0, params.Limit)
+		idMap  = make(map[int64]int, params.Limit)
+		where  = &strings.Builder{}
+		page   = &strings.Builder{}
+		counts = &strings.Builder{}
+		batch  = &pgx.Batch{}
+		args   = pgx.NamedArgs{
+			"search":     params.Search,
+			"year_added": params.YearAdded,
+			"min_rating": params.MinRating,
+		}
+	)
+
+	if params.Search != "" {
+		where.WriteString(` AND (
+			to_tsvector('simple', m.title) @@ plainto_tsquery('simple', @search)
+			OR EXISTS (
+			SELECT 1 FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+				AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', @search)
+			)
+		)`)
+	}
+	if params.YearAdded != 0 {
+		where.WriteString(" AND EXTRACT(YEAR FROM m.added_at) = @year_added")
+	}
+	if params.MinRating != 0 {
+		where.WriteString(" AND m.rating >= @min_rating")
+	}
+
+	page.WriteString(`
+		SELECT m.id, m.title, m.added_at, m.rating
+		FROM movies m
+		WHERE 1=1`)
+	page.WriteString(where.String())
+
+	order := "ASC"
+
+	if params.Desc {
+		order = "DESC"
+	}
+
+	switch params.Sort {
+	case "rating":
+		fmt.Fprintf(page, " ORDER BY m.rating %s", order)
+	case "title":
+		fmt.Fprintf(page, " ORDER BY m.title %s", order)
+	case "added_at":
+		fmt.Fprintf(page, " ORDER BY m.added_at %s", order)
+	default:
+		return result, fmt.Errorf("invalid sort")
+	}
+
+	if params.Limit < 1 || params.Limit > 1000 {
+		page.WriteString(" LIMIT 1000")
+	} else {
+		fmt.Fprintf(page, " LIMIT %d", params.Limit)
+	}
+
+	counts.WriteString(`
+		SELECT
+			CASE WHEN GROUPING(EXTRACT(YEAR FROM m.added_at)) = 0 THEN 'year' ELSE 'rating' END AS facet
+			, CAST(COALESCE(EXTRACT(YEAR FROM m.added_at), FLOOR(m.rating)) AS INT8) AS value
+			, COUNT(*) AS count
+		FROM movies m
+		WHERE 1=1`)
+	counts.WriteString(where.String())
+	counts.WriteString(" GROUP BY GROUPING SETS ((EXTRACT(YEAR FROM m.added_at)), (FLOOR(m.rating))) ORDER BY facet, value")
+
+	batch.Queue(page.String(), args).Query(func(rows pgx.Rows) error {
+		var err error
+
+		result.Movies, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (benchflix.Movie, error) {
+			var movie benchflix.Movie
+
+			if err := row.Scan(&movie.ID, &movie.Title, &movie.AddedAt, &movie.Rating); err != nil {
+				return movie, err
+			}
+
+			idMap[movie.ID] = index
+			index++
+			ids = append(ids, movie.ID)
+
+			return movie, nil
+		})
+
+		return err
+	})
+
+	batch.Queue(counts.String(), args).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			var (
+				facet string
+				f     benchflix.Facet
+			)
+
+			if err := rows.Scan(&facet, &f.Value, &f.Count); err != nil {
+				return err
+			}
+
+			if facet == "year" {
+				result.Years = append(result.Years, f)
+			} else {
+				result.Ratings = append(result.Ratings, f)
+			}
+		}
+
+		return rows.Err()
+	})
+
+	if err := r.Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return result, err
+	}
+
+	if !params.WithDirectors || len(ids) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.Pool.Query(ctx, `
+		SELECT
+			md.movie_id
+			, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+		FROM movie_directors md
+		JOIN people ON people.id = md.person_id
+		WHERE md.movie_id = ANY ($1)
+		GROUP BY md.movie_id;
+	`, ids)
+	if err != nil {
+		return result, err
+	}
+
+	movieDirectors, err := collectDirectors(rows)
+	if err != nil {
+		return result, err
+	}
+
+	for _, md := range movieDirectors {
+		result.Movies[idMap[md.MovieID]].Directors = md.Directors
+	}
+
+	return result, nil
+}
+
+func (r Repository) QueryTopRated(ctx context.Context, params benchflix.TopParams) ([]benchflix.TopMovie, error) {
+	rows, err := r.Pool.Query(ctx, `
+		WITH director_ratings AS (
+			SELECT
+				md.person_id
+				, CAST(AVG(m.rating) AS FLOAT8) AS avg_rating
+			FROM movie_directors md
+			JOIN movies m ON m.id = md.movie_id
+			GROUP BY md.person_id
+		), ranked AS (
+			SELECT
+				m.id
+				, m.title
+				, m.added_at
+				, m.rating
+				, CAST(EXTRACT(YEAR FROM m.added_at) AS INT8) AS year
+				, ROW_NUMBER() OVER (PARTITION BY EXTRACT(YEAR FROM m.added_at) ORDER BY m.rating DESC, m.id) AS position
+			FROM movies m
+			WHERE $1::NUMERIC = 0 OR m.rating >= $1
+		)
+		SELECT
+			r.id
+			, r.title
+			, r.added_at
+			, r.rating
+			, r.year
+			, r.position
+			, d.directors
+			, d.ratings
+		FROM ranked r
+		LEFT JOIN LATERAL (
+			SELECT
+				ARRAY_AGG(p.name ORDER BY p.name) AS directors
+				, ARRAY_AGG(dr.avg_rating ORDER BY p.name) AS ratings
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			JOIN director_ratings dr ON dr.person_id = md.person_id
+			WHERE md.movie_id = r.id
+		) d ON true
+		WHERE r.position <= CASE WHEN $2 BETWEEN 1 AND 10 THEN $2 ELSE 10 END
+		ORDER BY r.year, r.position;
+	`, params.MinRating, params.PerYear)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (benchflix.TopMovie, error) {
+		var m benchflix.TopMovie
+
+		if err := row.Scan(&m.ID, &m.Title, &m.AddedAt, &m.Rating, &m.Year, &m.Position, &m.Directors, &m.DirectorRatings); err != nil {
+			return m, err
+		}
+
+		return m, nil
+	})
+}
+
+func (r Repository) QueryCollaborators(ctx context.Context, params benchflix.GraphParams) ([]benchflix.Collaborator, error) {
+	rows, err := r.Pool.Query(ctx, `
+		WITH RECURSIVE graph (person_id, depth, path, movies) AS (
+			SELECT
+				CAST(p.id AS INT8)
+				, CAST(0 AS INT8)
+				, ARRAY[CAST(p.id AS INT8)]
+				, CAST(ARRAY[] AS TEXT[])
+			FROM people p
+			WHERE p.name = $1
+			UNION ALL
+			SELECT
+				CAST(b.person_id AS INT8)
+				, g.depth + 1
+				, g.path || CAST(b.person_id AS INT8)
+				, g.movies || m.title
+			FROM graph g
+			JOIN movie_directors a ON a.person_id = g.person_id
+			JOIN movie_directors b ON b.movie_id = a.movie_id
+			JOIN movies m ON m.id = a.movie_id
+			WHERE g.depth < CASE WHEN $2 BETWEEN 1 AND 3 THEN $2 ELSE 3 END
+			AND b.person_id <> ALL (g.path)
+		)
+		SELECT
+			c.person_id
+			, p.name
+			, c.depth
+			, c.movies
+		FROM (
+			SELECT DISTINCT ON (g.person_id)
+				g.person_id
+				, g.depth
+				, g.movies
+			FROM graph g
+			WHERE g.depth > 0
+			ORDER BY g.person_id, g.depth, g.movies
+		) c
+		JOIN people p ON p.id = c.person_id
+		ORDER BY c.depth, p.name;
+	`, params.Director, params.Depth)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (benchflix.Collaborator, error) {
+		var c benchflix.Collaborator
+
+		if err := row.Scan(&c.ID, &c.Name, &c.Depth, &c.Movies); err != nil {
+			return c, err
+		}
+
+		return c, nil
+	})
+}
+
+func (r Repository) QueryMovie(ctx context.Context, id int64) (benchflix.Movie, error) {
+	var movie benchflix.Movie
+
+	err := r.Pool.QueryRow(ctx, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, d.directors
+		FROM movies m
+		LEFT JOIN LATERAL (
+			SELECT ARRAY_AGG(p.name ORDER BY p.name) AS directors
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+		) d ON true
+		WHERE m.id = $1;
+	`, id).Scan(&movie.ID, &movie.Title, &movie.AddedAt, &movie.Rating, &movie.Directors)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return movie, benchflix.ErrNotFound
+	}
+
+	return movie, err
+}
+
+func (r Repository) QueryWide(ctx context.Context, params benchflix.WideParams) ([]benchflix.WideRow, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT
+			id
+			, int01
+			, int02
+			, int03
+			, int04
+			, int05
+			, int06
+			, int07
+			, int08
+			, int09
+			, int10
+			, int11
+			, int12
+			, num01
+			, num02
+			, num03
+			, num04
+			, num05
+			, num06
+			, num07
+			, num08
+			, num09
+			, num10
+			, num11
+			, num12
+			, time01
+			, time02
+			, time03
+			, time04
+			, time05
+			, time06
+			, time07
+			, time08
+			, time09
+			, time10
+			, time11
+			, time12
+			, tags01
+			, tags02
+			, tags03
+			, tags04
+			, tags05
+			, tags06
+			, tags07
+			, tags08
+			, tags09
+			, tags10
+			, tags11
+			, tags12
+			, note01
+			, note02
+			, note03
+			, note04
+			, note05
+			, note06
+			, note07
+			, note08
+			, note09
+			, note10
+			, note11
+			, note12
+		FROM wide_rows
+		WHERE id > $1
+		ORDER BY id
+		LIMIT CASE WHEN $2 BETWEEN 1 AND 1000 THEN $2 ELSE 1000 END;
+	`, params.After, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (benchflix.WideRow, error) {
+		var w benchflix.WideRow
+
+		if err := row.Scan(
+			&w.ID,
+			&w.Int01,
+			&w.Int02,
+			&w.Int03,
+			&w.Int04,
+			&w.Int05,
+			&w.Int06,
+			&w.Int07,
+			&w.Int08,
+			&w.Int09,
+			&w.Int10,
+			&w.Int11,
+			&w.Int12,
+			&w.Num01,
+			&w.Num02,
+			&w.Num03,
+			&w.Num04,
+			&w.Num05,
+			&w.Num06,
+			&w.Num07,
+			&w.Num08,
+			&w.Num09,
+			&w.Num10,
+			&w.Num11,
+			&w.Num12,
+			&w.Time01,
+			&w.Time02,
+			&w.Time03,
+			&w.Time04,
+			&w.Time05,
+			&w.Time06,
+			&w.Time07,
+			&w.Time08,
+			&w.Time09,
+			&w.Time10,
+			&w.Time11,
+			&w.Time12,
+			&w.Tags01,
+			&w.Tags02,
+			&w.Tags03,
+			&w.Tags04,
+			&w.Tags05,
+			&w.Tags06,
+			&w.Tags07,
+			&w.Tags08,
+			&w.Tags09,
+			&w.Tags10,
+			&w.Tags11,
+			&w.Tags12,
+			&w.Note01,
+			&w.Note02,
+			&w.Note03,
+			&w.Note04,
+			&w.Note05,
+			&w.Note06,
+			&w.Note07,
+			&w.Note08,
+			&w.Note09,
+			&w.Note10,
+			&w.Note11,
+			&w.Note12,
+		); err != nil {
+			return w, err
+		}
+
+		return w, nil
+	})
+}
+
+func (r Repository) QueryDirectors(ctx context.Context, params benchflix.PreloadParams) ([]benchflix.MovieDirectors, error) {
+	switch params.Strategy {
+	case benchflix.PreloadAny:
+		rows, err := r.Pool.Query(ctx, `
+			SELECT md.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+			FROM movie_directors md
+			JOIN people ON people.id = md.person_id
+			WHERE md.movie_id = ANY ($1)
+			GROUP BY md.movie_id;
+		`, params.IDs)
+		if err != nil {
+			return nil, err
+		}
+
+		return collectDirectors(rows)
+	case benchflix.PreloadUnnest:
+		rows, err := r.Pool.Query(ctx, `
+			SELECT i.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+			FROM unnest($1::INT8[]) WITH ORDINALITY AS i (movie_id, ord)
+			JOIN movie_directors md ON md.movie_id = i.movie_id
+			JOIN people ON people.id = md.person_id
+			GROUP BY i.ord, i.movie_id
+			ORDER BY i.ord;
+		`, params.IDs)
+		if err != nil {
+			return nil, err
+		}
+
+		return collectDirectors(rows)
+	case benchflix.PreloadTempTable:
+		tx, err := r.Pool.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() { _ = tx.Rollback(ctx) }()
+
+		if _, err = tx.Exec(ctx, `CREATE TEMP TABLE preload_ids (ord INT8, movie_id INT8) ON COMMIT DROP;`); err != nil {
+			return nil, err
+		}
+
+		if _, err = tx.CopyFrom(ctx, pgx.Identifier{"preload_ids"}, []string{"ord", "movie_id"},
+			pgx.CopyFromSlice(len(params.IDs), func(i int) ([]any, error) {
+				return []any{int64(i + 1), params.IDs[i]}, nil
+			}),
+		); err != nil {
+			return nil, err
+		}
+
+		rows, err := tx.Query(ctx, `
+			SELECT i.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+			FROM preload_ids i
+			JOIN movie_directors md ON md.movie_id = i.movie_id
+			JOIN people ON people.id = md.person_id
+			GROUP BY i.ord, i.movie_id
+			ORDER BY i.ord;
+		`)
+		if err != nil {
+			return nil, err
+		}
+
+		movieDirectors, err := collectDirectors(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		return movieDirectors, tx.Commit(ctx)
+	case benchflix.PreloadBatch:
+		var (
+			batch          = &pgx.Batch{}
+			movieDirectors = make([]benchflix.MovieDirectors, 0, len(params.IDs))
+		)
+
+		for _, id := range params.IDs {
+			batch.Queue(`
+			SELECT md.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+			FROM movie_directors md
+			JOIN people ON people.id = md.person_id
+			WHERE md.movie_id = $1
+			GROUP BY md.movie_id;
+			`, id).Query(func(rows pgx.Rows) error {
+				md, err := collectDirectors(rows)
+				if err != nil {
+					return err
+				}
+
+				movieDirectors = append(movieDirectors, md...)
+
+				return nil
+			})
+		}
+
+		if err := r.Pool.SendBatch(ctx, batch).Close(); err != nil {
+			return nil, err
+		}
+
+		return movieDirectors, nil
+	default:
+		return nil, fmt.Errorf("invalid strategy: %s", params.Strategy)
+	}
+}
+
+func collectDirectors(rows pgx.Rows) ([]benchflix.MovieDirectors, error) {
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (benchflix.MovieDirectors, error) {
+		var md benchflix.MovieDirectors
+
+		if err := row.Scan(&md.MovieID, &md.Directors); err != nil {
+			return md, err
+		}
+
+		return md, nil
+	})
+}
+
+func (r Repository) WithLoader(wait time.Duration, maxBatch int) benchflix.Repository {
+	r.Loader = benchflix.NewDirectorsLoader(wait, maxBatch, r.QueryDirectors)
+
+	return r
+}
+
+func (r Repository) PoolStats() benchflix.PoolStats {
+	return benchflix.PgxPoolStats(r.Pool)
+}
+
+func (r Repository) Close() error {
+	r.Pool.Close()
+
+	return nil
+}
@@ -0,0 +1,543 @@
+package benchflix
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"net"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Phases splits the duration of a repository call into building the query,
+// waiting for the database and scanning the rows.
+type Phases struct {
+	Build, DB, Scan time.Duration
+}
+
+type phaseKey struct{}
+
+type phaseRecorder struct {
+	start time.Time
+	first atomic.Int64
+	db    atomic.Int64
+}
+
+func (r *phaseRecorder) begin() {
+	r.first.CompareAndSwap(-1, int64(time.Since(r.start)))
+}
+
+func (r *phaseRecorder) observe(start time.Time) {
+	r.db.Add(int64(time.Since(start)))
+}
+
+func phaseRecorderFrom(ctx context.Context) *phaseRecorder {
+	rec, _ := ctx.Value(phaseKey{}).(*phaseRecorder)
+
+	return rec
+}
+
+// MeasurePhases runs query and splits its duration. Everything until the first
+// statement reaches the database is build time, waiting on the connection
+// (pgx) or inside the driver (database/sql) is db time and the rest is scan
+// time. Only repositories opened WithInstrumentation report db time.
+func MeasurePhases[P, R any](ctx context.Context, params P, query func(context.Context, P) (R, error)) (R, Phases, error) {
+	rec := &phaseRecorder{start: time.Now()}
+	rec.first.Store(-1)
+
+	result, err := query(context.WithValue(ctx, phaseKey{}, rec), params)
+
+	total := time.Since(rec.start)
+
+	build := time.Duration(rec.first.Load())
+	if build < 0 {
+		return result, Phases{Build: total}, err
+	}
+
+	db := min(time.Duration(rec.db.Load()), total-build)
+
+	return result, Phases{Build: build, DB: db, Scan: total - build - db}, err
+}
+
+// Connector opens dsn with d, through PhaseConnector if Instrument is set. Use
+// it with sql.OpenDB.
+func (o Options) Connector(d driver.Driver, dsn string) (driver.Connector, error) {
+	if o.Instrument {
+		return PhaseConnector(d, dsn)
+	}
+
+	return openConnector(d, dsn)
+}
+
+// InstrumentPgx installs a tracer and a dialer on cfg that attribute the time a
+// connection spends reading and writing during a query to MeasurePhases.
+func InstrumentPgx(cfg *pgx.ConnConfig) {
+	dial := cfg.DialFunc
+
+	cfg.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		return &phaseConn{Conn: conn}, nil
+	}
+
+	cfg.Tracer = phaseTracer{}
+}
+
+type phaseConn struct {
+	net.Conn
+
+	rec atomic.Pointer[phaseRecorder]
+}
+
+func (c *phaseConn) Read(b []byte) (int, error) {
+	rec := c.rec.Load()
+	if rec == nil {
+		return c.Conn.Read(b)
+	}
+
+	defer rec.observe(time.Now())
+
+	return c.Conn.Read(b)
+}
+
+func (c *phaseConn) Write(b []byte) (int, error) {
+	rec := c.rec.Load()
+	if rec == nil {
+		return c.Conn.Write(b)
+	}
+
+	defer rec.observe(time.Now())
+
+	return c.Conn.Write(b)
+}
+
+func phaseConnOf(conn *pgx.Conn) *phaseConn {
+	netConn := conn.PgConn().Conn()
+
+	// TLS connections wrap the dialed connection.
+	if tlsConn, ok := netConn.(interface{ NetConn() net.Conn }); ok {
+		netConn = tlsConn.NetConn()
+	}
+
+	pc, _ := netConn.(*phaseConn)
+
+	return pc
+}
+
+type phaseTracer struct{}
+
+var (
+	_ pgx.QueryTracer    = phaseTracer{}
+	_ pgx.BatchTracer    = phaseTracer{}
+	_ pgx.CopyFromTracer = phaseTracer{}
+)
+
+func (phaseTracer) start(ctx context.Context, conn *pgx.Conn) context.Context {
+	rec := phaseRecorderFrom(ctx)
+	if rec == nil {
+		return ctx
+	}
+
+	rec.begin()
+
+	if pc := phaseConnOf(conn); pc != nil {
+		pc.rec.Store(rec)
+	}
+
+	return ctx
+}
+
+func (phaseTracer) end(ctx context.Context, conn *pgx.Conn) {
+	if phaseRecorderFrom(ctx) == nil {
+		return
+	}
+
+	if pc := phaseConnOf(conn); pc != nil {
+		pc.rec.Store(nil)
+	}
+}
+
+func (t phaseTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	captureStatement(ctx, data.SQL, data.Args)
+
+	return t.start(ctx, conn)
+}
+
+func (t phaseTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, _ pgx.TraceQueryEndData) {
+	t.end(ctx, conn)
+}
+
+func (t phaseTracer) TraceBatchStart(ctx context.Context, conn *pgx.Conn, _ pgx.TraceBatchStartData) context.Context {
+	return t.start(ctx, conn)
+}
+
+func (phaseTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	captureStatement(ctx, data.SQL, data.Args)
+}
+
+func (t phaseTracer) TraceBatchEnd(ctx context.Context, conn *pgx.Conn, _ pgx.TraceBatchEndData) {
+	t.end(ctx, conn)
+}
+
+func (t phaseTracer) TraceCopyFromStart(ctx context.Context, conn *pgx.Conn, _ pgx.TraceCopyFromStartData) context.Context {
+	return t.start(ctx, conn)
+}
+
+func (t phaseTracer) TraceCopyFromEnd(ctx context.Context, conn *pgx.Conn, _ pgx.TraceCopyFromEndData) {
+	t.end(ctx, conn)
+}
+
+// PhaseConnector opens dsn with d and attributes the time spent inside the
+// driver to MeasurePhases. Use it with sql.OpenDB.
+func PhaseConnector(d driver.Driver, dsn string) (driver.Connector, error) {
+	connector, err := openConnector(d, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &phaseConnector{Connector: connector}, nil
+}
+
+// openConnector opens dsn with d like sql.Open does.
+func openConnector(d driver.Driver, dsn string) (driver.Connector, error) {
+	if dc, ok := d.(driver.DriverContext); ok {
+		return dc.OpenConnector(dsn)
+	}
+
+	return dsnConnector{dsn: dsn, driver: d}, nil
+}
+
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type phaseConnector struct {
+	driver.Connector
+}
+
+func (c *phaseConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return phaseDriverConn{Conn: conn}, nil
+}
+
+type phaseDriverConn struct {
+	driver.Conn
+}
+
+func (c phaseDriverConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c phaseDriverConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if rec := phaseRecorderFrom(ctx); rec != nil {
+		rec.begin()
+
+		defer rec.observe(time.Now())
+	}
+
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return phaseStmt{Stmt: stmt, conn: c.Conn, query: query}, nil
+}
+
+func (c phaseDriverConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	rec := phaseRecorderFrom(ctx)
+	if rec != nil {
+		rec.begin()
+
+		defer rec.observe(time.Now())
+	}
+
+	var (
+		tx  driver.Tx
+		err error
+	)
+
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = b.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.Conn.Begin() //nolint:staticcheck
+	}
+
+	if err != nil || rec == nil {
+		return tx, err
+	}
+
+	return phaseTx{Tx: tx, rec: rec}, nil
+}
+
+func (c phaseDriverConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	captureNamedStatement(ctx, query, args)
+
+	rec := phaseRecorderFrom(ctx)
+	if rec == nil {
+		return q.QueryContext(ctx, query, args)
+	}
+
+	rec.begin()
+
+	start := time.Now()
+
+	rows, err := q.QueryContext(ctx, query, args)
+
+	rec.observe(start)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return phaseRows{Rows: rows, rec: rec}, nil
+}
+
+func (c phaseDriverConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	captureNamedStatement(ctx, query, args)
+
+	if rec := phaseRecorderFrom(ctx); rec != nil {
+		rec.begin()
+
+		defer rec.observe(time.Now())
+	}
+
+	return e.ExecContext(ctx, query, args)
+}
+
+func (c phaseDriverConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c phaseDriverConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c phaseDriverConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+
+	return true
+}
+
+func (c phaseDriverConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+
+	return driver.ErrSkip
+}
+
+type phaseTx struct {
+	driver.Tx
+
+	rec *phaseRecorder
+}
+
+func (t phaseTx) Commit() error {
+	defer t.rec.observe(time.Now())
+
+	return t.Tx.Commit()
+}
+
+func (t phaseTx) Rollback() error {
+	defer t.rec.observe(time.Now())
+
+	return t.Tx.Rollback()
+}
+
+type phaseStmt struct {
+	driver.Stmt
+
+	conn  driver.Conn
+	query string
+}
+
+func (s phaseStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	captureNamedStatement(ctx, s.query, args)
+
+	rec := phaseRecorderFrom(ctx)
+	if rec != nil {
+		rec.begin()
+
+		defer rec.observe(time.Now())
+	}
+
+	var (
+		rows driver.Rows
+		err  error
+	)
+
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(values(args)) //nolint:staticcheck
+	}
+
+	if err != nil || rec == nil {
+		return rows, err
+	}
+
+	return phaseRows{Rows: rows, rec: rec}, nil
+}
+
+func (s phaseStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	captureNamedStatement(ctx, s.query, args)
+
+	if rec := phaseRecorderFrom(ctx); rec != nil {
+		rec.begin()
+
+		defer rec.observe(time.Now())
+	}
+
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return e.ExecContext(ctx, args)
+	}
+
+	return s.Stmt.Exec(values(args)) //nolint:staticcheck
+}
+
+func (s phaseStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+
+	if n, ok := s.conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+
+	return driver.ErrSkip
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+
+	return values
+}
+
+type phaseRows struct {
+	driver.Rows
+
+	rec *phaseRecorder
+}
+
+func (r phaseRows) Next(dest []driver.Value) error {
+	defer r.rec.observe(time.Now())
+
+	return r.Rows.Next(dest)
+}
+
+func (r phaseRows) Close() error {
+	defer r.rec.observe(time.Now())
+
+	return r.Rows.Close()
+}
+
+func (r phaseRows) HasNextResultSet() bool {
+	if n, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return n.HasNextResultSet()
+	}
+
+	return false
+}
+
+func (r phaseRows) NextResultSet() error {
+	if n, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		defer r.rec.observe(time.Now())
+
+		return n.NextResultSet()
+	}
+
+	return io.EOF
+}
+
+func (r phaseRows) ColumnTypeScanType(index int) reflect.Type {
+	if c, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return c.ColumnTypeScanType(index)
+	}
+
+	return reflect.TypeFor[any]()
+}
+
+func (r phaseRows) ColumnTypeDatabaseTypeName(index int) string {
+	if c, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return c.ColumnTypeDatabaseTypeName(index)
+	}
+
+	return ""
+}
+
+func (r phaseRows) ColumnTypeLength(index int) (int64, bool) {
+	if c, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return c.ColumnTypeLength(index)
+	}
+
+	return 0, false
+}
+
+func (r phaseRows) ColumnTypeNullable(index int) (bool, bool) {
+	if c, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return c.ColumnTypeNullable(index)
+	}
+
+	return false, false
+}
+
+func (r phaseRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if c, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return c.ColumnTypePrecisionScale(index)
+	}
+
+	return 0, 0, false
+}
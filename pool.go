@@ -0,0 +1,83 @@
+package benchflix
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Pooled is implemented by repositories that expose the counters of their
+// connection pool.
+type Pooled interface {
+	PoolStats() PoolStats
+}
+
+// PoolStats are the cumulative counters of a connection pool. Acquires,
+// EmptyAcquires and AcquireDuration are those of pgxpool: EmptyAcquires counts
+// acquires that found no idle connection and AcquireDuration is the total time
+// spent acquiring, they are only set if HasAcquires. Waits and WaitDuration are
+// those of database/sql, which only counts waiting at MaxOpenConns, they are
+// only set if HasWaits.
+type PoolStats struct {
+	HasAcquires             bool
+	Acquires, EmptyAcquires int64
+	AcquireDuration         time.Duration
+	HasWaits                bool
+	Waits                   int64
+	WaitDuration            time.Duration
+	NewConns, ClosedConns   int64
+}
+
+// Sub returns the counters accumulated since prev.
+func (s PoolStats) Sub(prev PoolStats) PoolStats {
+	return PoolStats{
+		HasAcquires:     s.HasAcquires,
+		Acquires:        s.Acquires - prev.Acquires,
+		EmptyAcquires:   s.EmptyAcquires - prev.EmptyAcquires,
+		AcquireDuration: s.AcquireDuration - prev.AcquireDuration,
+		HasWaits:        s.HasWaits,
+		Waits:           s.Waits - prev.Waits,
+		WaitDuration:    s.WaitDuration - prev.WaitDuration,
+		NewConns:        s.NewConns - prev.NewConns,
+		ClosedConns:     s.ClosedConns - prev.ClosedConns,
+	}
+}
+
+// NsPerAcquire is the average time of an acquire, zero without any.
+func (s PoolStats) NsPerAcquire() float64 {
+	if s.Acquires == 0 {
+		return 0
+	}
+
+	return float64(s.AcquireDuration) / float64(s.Acquires)
+}
+
+func PgxPoolStats(pool *pgxpool.Pool) PoolStats {
+	stat := pool.Stat()
+
+	return PoolStats{
+		HasAcquires:     true,
+		Acquires:        stat.AcquireCount(),
+		EmptyAcquires:   stat.EmptyAcquireCount(),
+		AcquireDuration: stat.AcquireDuration(),
+		NewConns:        stat.NewConnsCount(),
+		ClosedConns:     stat.MaxLifetimeDestroyCount() + stat.MaxIdleDestroyCount(),
+	}
+}
+
+// SQLPoolStats reads the counters of db. Every new connection is either still
+// open or was closed, connections closed after errors are not counted.
+func SQLPoolStats(db *sql.DB) PoolStats {
+	stat := db.Stats()
+
+	closed := stat.MaxIdleClosed + stat.MaxIdleTimeClosed + stat.MaxLifetimeClosed
+
+	return PoolStats{
+		HasWaits:     true,
+		Waits:        stat.WaitCount,
+		WaitDuration: stat.WaitDuration,
+		NewConns:     int64(stat.OpenConnections) + closed,
+		ClosedConns:  closed,
+	}
+}
@@ -0,0 +1,100 @@
+package benchflix
+
+import (
+	"strings"
+
+	"github.com/google/pprof/profile"
+)
+
+// ProfileGroup is a package group that samples of a profile count towards.
+type ProfileGroup struct {
+	Name     string
+	Prefixes []string
+}
+
+// ProfileGroups maps function name prefixes to the package group they count
+// towards. Samples are attributed to the innermost function of any group, the
+// standard library, runtime and the benchmark harness count as OtherGroup.
+var ProfileGroups = []ProfileGroup{
+	{"driver", []string{"github.com/jackc/", "github.com/lib/pq", "database/sql"}},
+	{"framework", []string{"gorm.io/", "github.com/jmoiron/sqlx", "github.com/Masterminds/squirrel", "github.com/go-sqlt/sqlt", "github.com/go-sqlt/structscan"}},
+	{"benchflix", []string{"github.com/go-sqlt/benchflix"}},
+}
+
+const (
+	OtherGroup = "other"
+	harness    = "github.com/go-sqlt/benchflix_test."
+)
+
+// AttributeSample returns the group and function a sample counts towards.
+func AttributeSample(s *profile.Sample) (string, string) {
+	var leaf string
+
+	for _, loc := range s.Location {
+		// inlined functions come first
+		for _, line := range loc.Line {
+			if line.Function == nil {
+				continue
+			}
+
+			if leaf == "" {
+				leaf = line.Function.Name
+			}
+
+			if strings.HasPrefix(line.Function.Name, harness) {
+				continue
+			}
+
+			for _, g := range ProfileGroups {
+				for _, prefix := range g.Prefixes {
+					if strings.HasPrefix(line.Function.Name, prefix) {
+						return g.Name, line.Function.Name
+					}
+				}
+			}
+		}
+	}
+
+	return OtherGroup, leaf
+}
+
+// GroupedProfile is one sample type of a profile added up per group and per
+// function of a group.
+type GroupedProfile struct {
+	Total     int64
+	Groups    map[string]int64
+	Functions map[string]map[string]int64
+}
+
+// GroupProfile adds up the values at index of the samples of p.
+func GroupProfile(p *profile.Profile, index int) GroupedProfile {
+	grouped := GroupedProfile{
+		Groups:    map[string]int64{},
+		Functions: map[string]map[string]int64{},
+	}
+
+	for _, s := range p.Sample {
+		value := s.Value[index]
+		group, function := AttributeSample(s)
+
+		grouped.Total += value
+		grouped.Groups[group] += value
+
+		if grouped.Functions[group] == nil {
+			grouped.Functions[group] = map[string]int64{}
+		}
+
+		grouped.Functions[group][function] += value
+	}
+
+	return grouped
+}
+
+// DiffProfiles returns what after adds to before, e.g. the allocations made
+// between two allocs profiles. Samples without a difference are dropped.
+func DiffProfiles(before, after *profile.Profile) (*profile.Profile, error) {
+	before = before.Copy()
+	before.Scale(-1)
+
+	return profile.Merge([]*profile.Profile{after, before})
+}
@@ -0,0 +1,178 @@
+package benchflix
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ory/dockertest"
+)
+
+// ResultsVersion is the version of the results schema written by the harness.
+// Readers reject other versions.
+const ResultsVersion = 1
+
+// Results are the benchmark results together with the environment that
+// produced them.
+type Results struct {
+	Version     int         `json:"version"`
+	Environment Environment `json:"environment"`
+	Benchmarks  []Result    `json:"benchmarks"`
+}
+
+// Result is one run of a sub-benchmark. Metrics are keyed by unit as in the
+// go test output, e.g. ns/op, B/op, allocs/op and those of b.ReportMetric.
+type Result struct {
+	Name    string             `json:"name"`
+	N       int                `json:"n"`
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+// Environment describes the machine, database and inputs of a run. Hashes are
+// hex encoded sha256 sums, empty if the file is missing.
+type Environment struct {
+	Date        time.Time        `json:"date"`
+	GoVersion   string           `json:"go_version"`
+	GOOS        string           `json:"goos"`
+	GOARCH      string           `json:"goarch"`
+	GOMAXPROCS  int              `json:"gomaxprocs"`
+	CPU         string           `json:"cpu"`
+	Postgres    string           `json:"postgres"`
+	Image       string           `json:"image"`
+	ImageDigest string           `json:"image_digest"`
+	GoSum       string           `json:"go_sum_sha256"`
+	Params      string           `json:"params_sha256"`
+	Rows        map[string]int64 `json:"rows"`
+}
+
+// NewEnvironment describes the current process. go.sum and params.json are
+// read from the working directory.
+func NewEnvironment() Environment {
+	return Environment{
+		Date:       time.Now().UTC(),
+		GoVersion:  runtime.Version(),
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		CPU:        cpuName(),
+		GoSum:      hashFile("go.sum"),
+		Params:     hashFile("params.json"),
+	}
+}
+
+// DescribePostgres adds the version, image and row counts of the database
+// started by InitializePostgres.
+func (e *Environment) DescribePostgres(ctx context.Context, conn string, resource *dockertest.Resource) error {
+	db, err := pgxpool.New(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	if err = db.QueryRow(ctx, "SELECT version()").Scan(&e.Postgres); err != nil {
+		return err
+	}
+
+	e.Rows = map[string]int64{}
+
+	for _, table := range []string{"movies", "people", "movie_directors", "wide_rows"} {
+		var count int64
+
+		if err = db.QueryRow(ctx, "SELECT count(*) FROM "+table).Scan(&count); err != nil {
+			return err
+		}
+
+		e.Rows[table] = count
+	}
+
+	if resource.Container.Config != nil {
+		e.Image = resource.Container.Config.Image
+	}
+
+	e.ImageDigest = resource.Container.Image
+
+	image, err := Pool.Client.InspectImage(resource.Container.Image)
+	if err != nil {
+		return err
+	}
+
+	if len(image.RepoDigests) > 0 {
+		e.ImageDigest = image.RepoDigests[0]
+	}
+
+	return nil
+}
+
+// ReadResults reads results written by the harness.
+func ReadResults(r io.Reader) (Results, error) {
+	var results Results
+
+	if err := json.NewDecoder(r).Decode(&results); err != nil {
+		return results, fmt.Errorf("results: %w", err)
+	}
+
+	if results.Version != ResultsVersion {
+		return results, fmt.Errorf("results: unsupported version %d, want %d", results.Version, ResultsVersion)
+	}
+
+	return results, nil
+}
+
+func hashFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// cpuName returns the model name like go test's cpu line, which is only known
+// on linux.
+func cpuName() string {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+
+	defer f.Close()
+
+	scan := bufio.NewScanner(f)
+
+	for scan.Scan() {
+		key, value, ok := strings.Cut(scan.Text(), ":")
+		if ok && strings.TrimSpace(key) == "model name" {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
+
+// peekNonSpace returns the first byte that is not white space without
+// consuming anything, so that the readers still count the leading lines.
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for n := 1; ; n++ {
+		b, err := r.Peek(n)
+		if err != nil {
+			return 0, err
+		}
+
+		if c := b[n-1]; !unicode.IsSpace(rune(c)) {
+			return c, nil
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package benchflix
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Runner drives repository calls with its own timing loop, independent of
+// go test. With a Duration of zero it runs until the context is canceled,
+// which makes it usable as a long-lived process for soak tests and profiling.
+type Runner struct {
+	Concurrency int
+	Duration    time.Duration
+	Warmup      Warmup
+	// Report is called every Interval with the results accumulated so far.
+	Interval time.Duration
+	Report   func(RunResult)
+}
+
+// RunResult is the outcome of a run. Allocations are process wide deltas of
+// runtime.MemStats, the pool counters are only set for Pooled repositories and
+// the acquire counters only for pgxpool, see PoolStats.
+type RunResult struct {
+	Framework   string        `json:"framework"`
+	Szenario    string        `json:"szenario"`
+	Params      int           `json:"params"`
+	Concurrency int           `json:"concurrency"`
+	WarmupCalls int           `json:"warmup_calls"`
+	Calls       int64         `json:"calls"`
+	Elapsed     time.Duration `json:"elapsed_ns"`
+	NsPerOp     float64       `json:"ns_per_op"`
+	P50         time.Duration `json:"p50_ns"`
+	P90         time.Duration `json:"p90_ns"`
+	P99         time.Duration `json:"p99_ns"`
+	Max         time.Duration `json:"max_ns"`
+	BytesPerOp  float64       `json:"bytes_per_op"`
+	AllocsPerOp float64       `json:"allocs_per_op"`
+
+	AcquiresPerOp      float64 `json:"acquires_per_op,omitempty"`
+	EmptyAcquiresPerOp float64 `json:"empty_acquires_per_op,omitempty"`
+	NsPerAcquire       float64 `json:"ns_per_acquire,omitempty"`
+	WaitsPerOp         float64 `json:"waits_per_op,omitempty"`
+	WaitNsPerOp        float64 `json:"wait_ns_per_op,omitempty"`
+	NewConns           int64   `json:"new_conns,omitempty"`
+	ClosedConns        int64   `json:"closed_conns,omitempty"`
+}
+
+// Run calls query with params round robin until the runner is done. It returns
+// ErrSkip if the repository does not support the query.
+func Run[P, R any](ctx context.Context, runner Runner, repo Repository, query func(Repository, context.Context, P) (R, error), params []P) (RunResult, error) {
+	result := RunResult{Params: len(params), Concurrency: max(runner.Concurrency, 1)}
+
+	if len(params) == 0 {
+		return result, errors.New("no params")
+	}
+
+	if _, err := query(repo, ctx, params[0]); err != nil {
+		return result, err
+	}
+
+	size := len(params)
+
+	warmed, err := runner.Warmup.Run(result.Concurrency, func(i int) error {
+		_, err := query(repo, ctx, params[i%size])
+
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.WarmupCalls = warmed.Calls
+
+	if runner.Duration > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, runner.Duration)
+
+		defer cancel()
+	}
+
+	var (
+		mu        sync.Mutex
+		histogram = &latencyHistogram{}
+		pooled, _ = repo.(Pooled)
+		before    = snapshot(pooled)
+		group     errgroup.Group
+	)
+
+	report := func() RunResult {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return before.result(result, histogram, snapshot(pooled))
+	}
+
+	for w := range result.Concurrency {
+		group.Go(func() error {
+			local := &latencyHistogram{}
+
+			// merge every few calls so that Report sees progress
+			flush := func() {
+				mu.Lock()
+				histogram.merge(local)
+				mu.Unlock()
+
+				*local = latencyHistogram{}
+			}
+
+			defer flush()
+
+			for i := w; ctx.Err() == nil; i += result.Concurrency {
+				start := time.Now()
+
+				if _, err := query(repo, ctx, params[i%size]); err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+
+					return err
+				}
+
+				local.add(time.Since(start))
+
+				if local.count%1000 == 0 {
+					flush()
+				}
+			}
+
+			return nil
+		})
+	}
+
+	if runner.Report != nil && runner.Interval > 0 {
+		var (
+			done     = make(chan struct{})
+			reporter sync.WaitGroup
+		)
+
+		defer reporter.Wait()
+		defer close(done)
+
+		reporter.Add(1)
+
+		go func() {
+			defer reporter.Done()
+
+			ticker := time.NewTicker(runner.Interval)
+
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					runner.Report(report())
+				}
+			}
+		}()
+	}
+
+	if err := group.Wait(); err != nil {
+		return result, err
+	}
+
+	return report(), nil
+}
+
+type runSnapshot struct {
+	start  time.Time
+	mem    runtime.MemStats
+	pool   PoolStats
+	pooled bool
+}
+
+func snapshot(pooled Pooled) runSnapshot {
+	s := runSnapshot{start: time.Now(), pooled: pooled != nil}
+
+	runtime.ReadMemStats(&s.mem)
+
+	if pooled != nil {
+		s.pool = pooled.PoolStats()
+	}
+
+	return s
+}
+
+func (s runSnapshot) result(result RunResult, histogram *latencyHistogram, now runSnapshot) RunResult {
+	result.Calls = histogram.count
+	result.Elapsed = now.start.Sub(s.start)
+
+	if result.Calls == 0 {
+		return result
+	}
+
+	n := float64(result.Calls)
+
+	result.NsPerOp = float64(result.Elapsed) / n
+	result.P50 = histogram.quantile(0.5)
+	result.P90 = histogram.quantile(0.9)
+	result.P99 = histogram.quantile(0.99)
+	result.Max = histogram.max
+	result.BytesPerOp = float64(now.mem.TotalAlloc-s.mem.TotalAlloc) / n
+	result.AllocsPerOp = float64(now.mem.Mallocs-s.mem.Mallocs) / n
+
+	if s.pooled {
+		pool := now.pool.Sub(s.pool)
+
+		if pool.HasAcquires {
+			result.AcquiresPerOp = float64(pool.Acquires) / n
+			result.EmptyAcquiresPerOp = float64(pool.EmptyAcquires) / n
+			result.NsPerAcquire = pool.NsPerAcquire()
+		}
+
+		if pool.HasWaits {
+			result.WaitsPerOp = float64(pool.Waits) / n
+			result.WaitNsPerOp = float64(pool.WaitDuration) / n
+		}
+
+		result.NewConns = pool.NewConns
+		result.ClosedConns = pool.ClosedConns
+	}
+
+	return result
+}
+
+// latencyHistogram has buckets that grow by 1%, so quantiles are accurate to
+// 1% with constant memory however long a run takes.
+type latencyHistogram struct {
+	buckets [latencyBuckets]int64
+	count   int64
+	max     time.Duration
+}
+
+const (
+	latencyGrowth  = 1.01
+	latencyBuckets = 2500 // 1ns to about 60s
+)
+
+func (h *latencyHistogram) add(d time.Duration) {
+	i := 0
+	if d > 1 {
+		i = min(int(math.Log(float64(d))/math.Log(latencyGrowth)), latencyBuckets-1)
+	}
+
+	h.buckets[i]++
+	h.count++
+	h.max = max(h.max, d)
+}
+
+func (h *latencyHistogram) merge(o *latencyHistogram) {
+	for i, c := range o.buckets {
+		h.buckets[i] += c
+	}
+
+	h.count += o.count
+	h.max = max(h.max, o.max)
+}
+
+func (h *latencyHistogram) quantile(q float64) time.Duration {
+	rank := int64(math.Ceil(q * float64(h.count)))
+
+	var seen int64
+
+	for i, c := range h.buckets {
+		seen += c
+
+		if seen >= rank && c > 0 {
+			return min(time.Duration(math.Pow(latencyGrowth, float64(i+1))), h.max)
+		}
+	}
+
+	return h.max
+}
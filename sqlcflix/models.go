@@ -6,13 +6,19 @@ package sqlcflix
 
 import (
 	"time"
+
+	"github.com/go-sqlt/benchflix"
 )
 
 type Movie struct {
-	ID      int64     `db:"id" json:"id"`
-	Title   string    `db:"title" json:"title"`
-	AddedAt time.Time `db:"added_at" json:"added_at"`
-	Rating  float64   `db:"rating" json:"rating"`
+	ID       int64               `db:"id" json:"id"`
+	Title    string              `db:"title" json:"title"`
+	AddedAt  time.Time           `db:"added_at" json:"added_at"`
+	Rating   float64             `db:"rating" json:"rating"`
+	Runtime  *int64              `db:"runtime" json:"runtime"`
+	Budget   *int64              `db:"budget" json:"budget"`
+	Tagline  *string             `db:"tagline" json:"tagline"`
+	Metadata *benchflix.Metadata `db:"metadata" json:"metadata"`
 }
 
 type MovieDirector struct {
@@ -24,3 +30,67 @@ type Person struct {
 	ID   int64  `db:"id" json:"id"`
 	Name string `db:"name" json:"name"`
 }
+
+type WideRow struct {
+	ID     int64     `db:"id" json:"id"`
+	Int01  int64     `db:"int01" json:"int01"`
+	Int02  int64     `db:"int02" json:"int02"`
+	Int03  int64     `db:"int03" json:"int03"`
+	Int04  int64     `db:"int04" json:"int04"`
+	Int05  int64     `db:"int05" json:"int05"`
+	Int06  int64     `db:"int06" json:"int06"`
+	Int07  int64     `db:"int07" json:"int07"`
+	Int08  int64     `db:"int08" json:"int08"`
+	Int09  int64     `db:"int09" json:"int09"`
+	Int10  int64     `db:"int10" json:"int10"`
+	Int11  int64     `db:"int11" json:"int11"`
+	Int12  int64     `db:"int12" json:"int12"`
+	Num01  float64   `db:"num01" json:"num01"`
+	Num02  float64   `db:"num02" json:"num02"`
+	Num03  float64   `db:"num03" json:"num03"`
+	Num04  float64   `db:"num04" json:"num04"`
+	Num05  float64   `db:"num05" json:"num05"`
+	Num06  float64   `db:"num06" json:"num06"`
+	Num07  float64   `db:"num07" json:"num07"`
+	Num08  float64   `db:"num08" json:"num08"`
+	Num09  float64   `db:"num09" json:"num09"`
+	Num10  float64   `db:"num10" json:"num10"`
+	Num11  float64   `db:"num11" json:"num11"`
+	Num12  float64   `db:"num12" json:"num12"`
+	Time01 time.Time `db:"time01" json:"time01"`
+	Time02 time.Time `db:"time02" json:"time02"`
+	Time03 time.Time `db:"time03" json:"time03"`
+	Time04 time.Time `db:"time04" json:"time04"`
+	Time05 time.Time `db:"time05" json:"time05"`
+	Time06 time.Time `db:"time06" json:"time06"`
+	Time07 time.Time `db:"time07" json:"time07"`
+	Time08 time.Time `db:"time08" json:"time08"`
+	Time09 time.Time `db:"time09" json:"time09"`
+	Time10 time.Time `db:"time10" json:"time10"`
+	Time11 time.Time `db:"time11" json:"time11"`
+	Time12 time.Time `db:"time12" json:"time12"`
+	Tags01 []string  `db:"tags01" json:"tags01"`
+	Tags02 []string  `db:"tags02" json:"tags02"`
+	Tags03 []string  `db:"tags03" json:"tags03"`
+	Tags04 []string  `db:"tags04" json:"tags04"`
+	Tags05 []string  `db:"tags05" json:"tags05"`
+	Tags06 []string  `db:"tags06" json:"tags06"`
+	Tags07 []string  `db:"tags07" json:"tags07"`
+	Tags08 []string  `db:"tags08" json:"tags08"`
+	Tags09 []string  `db:"tags09" json:"tags09"`
+	Tags10 []string  `db:"tags10" json:"tags10"`
+	Tags11 []string  `db:"tags11" json:"tags11"`
+	Tags12 []string  `db:"tags12" json:"tags12"`
+	Note01 *string   `db:"note01" json:"note01"`
+	Note02 *string   `db:"note02" json:"note02"`
+	Note03 *string   `db:"note03" json:"note03"`
+	Note04 *string   `db:"note04" json:"note04"`
+	Note05 *string   `db:"note05" json:"note05"`
+	Note06 *string   `db:"note06" json:"note06"`
+	Note07 *string   `db:"note07" json:"note07"`
+	Note08 *string   `db:"note08" json:"note08"`
+	Note09 *string   `db:"note09" json:"note09"`
+	Note10 *string   `db:"note10" json:"note10"`
+	Note11 *string   `db:"note11" json:"note11"`
+	Note12 *string   `db:"note12" json:"note12"`
+}
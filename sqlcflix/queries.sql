@@ -60,4 +60,346 @@ SELECT
 FROM movie_directors md
 JOIN people ON people.id = md.person_id
 WHERE md.movie_id = ANY ($1::INT8[])
-GROUP BY md.movie_id;
\ No newline at end of file
+GROUP BY md.movie_id;
+
+-- name: QueryDetails :many
+SELECT
+    m.id
+    , m.title
+    , m.added_at
+    , m.rating
+    , m.runtime
+    , m.budget
+    , m.tagline
+    , m.metadata
+FROM movies m
+WHERE
+    (
+        sqlc.narg(search)::TEXT = ''
+        OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', sqlc.narg(search))
+        OR EXISTS (
+            SELECT 1
+            FROM movie_directors md
+            JOIN people p ON p.id = md.person_id
+            WHERE md.movie_id = m.id
+            AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', sqlc.narg(search))
+        )
+    )
+    AND (sqlc.narg(year_added)::INT8 = 0 OR EXTRACT(YEAR FROM m.added_at) = sqlc.narg(year_added))
+    AND (sqlc.narg(min_rating)::FLOAT8 = 0 OR m.rating >= sqlc.narg(min_rating))
+ORDER BY m.rating DESC
+LIMIT CASE WHEN sqlc.narg('limit')::INT4 BETWEEN 1 AND 1000 THEN sqlc.narg('limit') ELSE 1000 END;
+
+-- name: QuerySearch :many
+SELECT
+    m.id
+    , m.title
+    , m.added_at
+    , m.rating
+    , d.directors::TEXT[] AS directors
+    , ts_rank(
+        setweight(to_tsvector('simple', m.title), 'A')
+        || setweight(to_tsvector('simple', COALESCE(d.names, '')), 'B'),
+        q.query
+    )::FLOAT8 AS rank
+    , ts_headline('simple', m.title || ' / ' || COALESCE(d.names, ''), q.query)::TEXT AS headline
+FROM movies m
+CROSS JOIN LATERAL (
+    SELECT CASE
+        WHEN sqlc.arg(websearch)::BOOL THEN websearch_to_tsquery('simple', sqlc.narg(search)::TEXT)
+        ELSE plainto_tsquery('simple', sqlc.narg(search)::TEXT)
+    END AS query
+) q
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(p.name ORDER BY p.name) AS directors
+        , STRING_AGG(p.name, ' ' ORDER BY p.name) AS names
+    FROM movie_directors md
+    JOIN people p ON p.id = md.person_id
+    WHERE md.movie_id = m.id
+) d ON true
+WHERE
+    to_tsvector('simple', m.title) @@ q.query
+    OR EXISTS (
+        SELECT 1
+        FROM movie_directors md
+        JOIN people p ON p.id = md.person_id
+        WHERE md.movie_id = m.id
+        AND to_tsvector('simple', p.name) @@ q.query
+    )
+ORDER BY rank DESC, m.id
+LIMIT CASE WHEN sqlc.narg('limit')::INT4 BETWEEN 1 AND 1000 THEN sqlc.narg('limit') ELSE 1000 END;
+
+-- name: QueryFacetCounts :many
+SELECT
+    (CASE WHEN GROUPING(EXTRACT(YEAR FROM m.added_at)) = 0 THEN 'year' ELSE 'rating' END)::TEXT AS facet
+    , CAST(COALESCE(EXTRACT(YEAR FROM m.added_at), FLOOR(m.rating)) AS INT8) AS value
+    , COUNT(*) AS count
+FROM movies m
+WHERE
+    (
+        sqlc.narg(search)::TEXT = ''
+        OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', sqlc.narg(search))
+        OR EXISTS (
+            SELECT 1
+            FROM movie_directors md
+            JOIN people p ON p.id = md.person_id
+            WHERE md.movie_id = m.id
+            AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', sqlc.narg(search))
+        )
+    )
+    AND (sqlc.narg(year_added)::INT8 = 0 OR EXTRACT(YEAR FROM m.added_at) = sqlc.narg(year_added))
+    AND (sqlc.narg(min_rating)::FLOAT8 = 0 OR m.rating >= sqlc.narg(min_rating))
+GROUP BY GROUPING SETS ((EXTRACT(YEAR FROM m.added_at)), (FLOOR(m.rating)))
+ORDER BY facet, value;
+
+-- name: QueryFacetsPage :many
+SELECT
+    m.id
+    , m.title
+    , m.added_at
+    , m.rating
+FROM movies m
+WHERE
+    (
+        sqlc.narg(search)::TEXT = ''
+        OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', sqlc.narg(search))
+        OR EXISTS (
+            SELECT 1
+            FROM movie_directors md
+            JOIN people p ON p.id = md.person_id
+            WHERE md.movie_id = m.id
+            AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', sqlc.narg(search))
+        )
+    )
+    AND (sqlc.narg(year_added)::INT8 = 0 OR EXTRACT(YEAR FROM m.added_at) = sqlc.narg(year_added))
+    AND (sqlc.narg(min_rating)::FLOAT8 = 0 OR m.rating >= sqlc.narg(min_rating))
+ORDER BY
+    CASE WHEN sqlc.narg(sort)::TEXT = 'title' AND NOT sqlc.arg(descending)::BOOL THEN m.title END ASC
+    , CASE WHEN sqlc.narg(sort)::TEXT = 'title' AND sqlc.arg(descending)::BOOL THEN m.title END DESC
+    , CASE WHEN sqlc.narg(sort)::TEXT = 'added_at' AND NOT sqlc.arg(descending)::BOOL THEN m.added_at END ASC
+    , CASE WHEN sqlc.narg(sort)::TEXT = 'added_at' AND sqlc.arg(descending)::BOOL THEN m.added_at END DESC
+    , CASE WHEN sqlc.narg(sort)::TEXT = 'rating' AND NOT sqlc.arg(descending)::BOOL THEN m.rating END ASC
+    , CASE WHEN sqlc.narg(sort)::TEXT = 'rating' AND sqlc.arg(descending)::BOOL THEN m.rating END DESC
+LIMIT CASE WHEN sqlc.narg('limit')::INT4 BETWEEN 1 AND 1000 THEN sqlc.narg('limit') ELSE 1000 END;
+
+-- name: QueryTopRated :many
+WITH director_ratings AS (
+    SELECT
+        md.person_id
+        , CAST(AVG(m.rating) AS FLOAT8) AS avg_rating
+    FROM movie_directors md
+    JOIN movies m ON m.id = md.movie_id
+    GROUP BY md.person_id
+), ranked AS (
+    SELECT
+        m.id
+        , m.title
+        , m.added_at
+        , m.rating
+        , CAST(EXTRACT(YEAR FROM m.added_at) AS INT8) AS year
+        , ROW_NUMBER() OVER (PARTITION BY EXTRACT(YEAR FROM m.added_at) ORDER BY m.rating DESC, m.id) AS position
+    FROM movies m
+    WHERE sqlc.arg(min_rating)::FLOAT8 = 0 OR m.rating >= sqlc.arg(min_rating)
+)
+SELECT
+    r.id
+    , r.title
+    , r.added_at
+    , r.rating
+    , r.year
+    , r.position
+    , d.directors::TEXT[] AS directors
+    , d.ratings::FLOAT8[] AS ratings
+FROM ranked r
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(p.name ORDER BY p.name) AS directors
+        , ARRAY_AGG(dr.avg_rating ORDER BY p.name) AS ratings
+    FROM movie_directors md
+    JOIN people p ON p.id = md.person_id
+    JOIN director_ratings dr ON dr.person_id = md.person_id
+    WHERE md.movie_id = r.id
+) d ON true
+WHERE r.position <= CASE WHEN sqlc.arg(per_year)::INT4 BETWEEN 1 AND 10 THEN sqlc.arg(per_year) ELSE 10 END
+ORDER BY r.year, r.position;
+
+-- name: QueryCollaborators :many
+WITH RECURSIVE graph (person_id, depth, path, movies) AS (
+    SELECT
+        CAST(p.id AS INT8)
+        , CAST(0 AS INT8)
+        , ARRAY[CAST(p.id AS INT8)]
+        , CAST(ARRAY[] AS TEXT[])
+    FROM people p
+    WHERE p.name = sqlc.arg(director)::TEXT
+    UNION ALL
+    SELECT
+        CAST(b.person_id AS INT8)
+        , g.depth + 1
+        , g.path || CAST(b.person_id AS INT8)
+        , g.movies || m.title
+    FROM graph g
+    JOIN movie_directors a ON a.person_id = g.person_id
+    JOIN movie_directors b ON b.movie_id = a.movie_id
+    JOIN movies m ON m.id = a.movie_id
+    WHERE g.depth < CASE WHEN sqlc.arg(depth)::INT4 BETWEEN 1 AND 3 THEN sqlc.arg(depth) ELSE 3 END
+    AND b.person_id <> ALL (g.path)
+)
+SELECT
+    c.person_id
+    , p.name
+    , c.depth
+    , c.movies
+FROM (
+    SELECT DISTINCT ON (g.person_id)
+        g.person_id
+        , g.depth
+        , g.movies
+    FROM graph g
+    WHERE g.depth > 0
+    ORDER BY g.person_id, g.depth, g.movies
+) c
+JOIN people p ON p.id = c.person_id
+ORDER BY c.depth, p.name;
+
+-- name: QueryMovie :one
+SELECT
+    m.id
+    , m.title
+    , m.added_at
+    , m.rating
+    , d.directors
+FROM movies m
+LEFT JOIN LATERAL (
+    SELECT ARRAY_AGG(p.name ORDER BY p.name) AS directors
+    FROM movie_directors md
+    JOIN people p ON p.id = md.person_id
+    WHERE md.movie_id = m.id
+) d ON true
+WHERE m.id = sqlc.arg(id)::INT8;
+
+-- name: QueryWide :many
+SELECT
+    id
+    , int01
+    , int02
+    , int03
+    , int04
+    , int05
+    , int06
+    , int07
+    , int08
+    , int09
+    , int10
+    , int11
+    , int12
+    , num01
+    , num02
+    , num03
+    , num04
+    , num05
+    , num06
+    , num07
+    , num08
+    , num09
+    , num10
+    , num11
+    , num12
+    , time01
+    , time02
+    , time03
+    , time04
+    , time05
+    , time06
+    , time07
+    , time08
+    , time09
+    , time10
+    , time11
+    , time12
+    , tags01
+    , tags02
+    , tags03
+    , tags04
+    , tags05
+    , tags06
+    , tags07
+    , tags08
+    , tags09
+    , tags10
+    , tags11
+    , tags12
+    , note01
+    , note02
+    , note03
+    , note04
+    , note05
+    , note06
+    , note07
+    , note08
+    , note09
+    , note10
+    , note11
+    , note12
+FROM wide_rows
+WHERE id > sqlc.arg(after)::INT8
+ORDER BY id
+LIMIT CASE WHEN sqlc.arg('limit')::INT4 BETWEEN 1 AND 1000 THEN sqlc.arg('limit') ELSE 1000 END;
+
+-- name: QueryDirectorsBatch :batchmany
+SELECT
+    md.movie_id
+    , ARRAY_AGG(people.name ORDER BY people.name)::TEXT[] AS directors
+FROM movie_directors md
+JOIN people ON people.id = md.person_id
+WHERE md.movie_id = $1
+GROUP BY md.movie_id;
+
+-- name: QueryDirectorsUnnest :many
+SELECT
+    CAST(i.movie_id AS INT8) AS movie_id
+    , ARRAY_AGG(people.name ORDER BY people.name)::TEXT[] AS directors
+FROM unnest($1::INT8[]) WITH ORDINALITY AS i (movie_id, ord)
+JOIN movie_directors md ON md.movie_id = i.movie_id
+JOIN people ON people.id = md.person_id
+GROUP BY i.ord, i.movie_id
+ORDER BY i.ord;
+
+-- name: QueryMovieDirectors :many
+SELECT people.name
+FROM movie_directors md
+JOIN people ON people.id = md.person_id
+WHERE md.movie_id = $1
+ORDER BY people.name;
+
+-- name: QueryJSON :many
+SELECT
+    m.id
+    , m.title
+    , m.added_at
+    , m.rating
+    , COALESCE(d.directors, '[]')::JSON AS directors
+FROM movies m
+LEFT JOIN LATERAL (
+    SELECT json_agg(json_build_object('id', p.id, 'name', p.name) ORDER BY p.name) AS directors
+    FROM movie_directors md
+    JOIN people p ON p.id = md.person_id
+    WHERE md.movie_id = m.id
+) d ON true
+WHERE
+    (
+        sqlc.narg(search)::TEXT = ''
+        OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', sqlc.narg(search))
+        OR EXISTS (
+            SELECT 1
+            FROM movie_directors md
+            JOIN people p ON p.id = md.person_id
+            WHERE md.movie_id = m.id
+            AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', sqlc.narg(search))
+        )
+    )
+    AND (sqlc.narg(year_added)::INT8 = 0 OR EXTRACT(YEAR FROM m.added_at) = sqlc.narg(year_added))
+    AND (sqlc.narg(min_rating)::FLOAT8 = 0 OR m.rating >= sqlc.narg(min_rating))
+ORDER BY m.rating DESC
+LIMIT CASE WHEN sqlc.narg('limit')::INT4 BETWEEN 1 AND 1000 THEN sqlc.narg('limit') ELSE 1000 END;
@@ -88,6 +88,153 @@ func (q *Queries) Query(ctx context.Context, arg QueryParams) ([]QueryRow, error
 	return items, nil
 }
 
+const queryCollaborators = `-- name: QueryCollaborators :many
+WITH RECURSIVE graph (person_id, depth, path, movies) AS (
+    SELECT
+        CAST(p.id AS INT8)
+        , CAST(0 AS INT8)
+        , ARRAY[CAST(p.id AS INT8)]
+        , CAST(ARRAY[] AS TEXT[])
+    FROM people p
+    WHERE p.name = $1::TEXT
+    UNION ALL
+    SELECT
+        CAST(b.person_id AS INT8)
+        , g.depth + 1
+        , g.path || CAST(b.person_id AS INT8)
+        , g.movies || m.title
+    FROM graph g
+    JOIN movie_directors a ON a.person_id = g.person_id
+    JOIN movie_directors b ON b.movie_id = a.movie_id
+    JOIN movies m ON m.id = a.movie_id
+    WHERE g.depth < CASE WHEN $2::INT4 BETWEEN 1 AND 3 THEN $2 ELSE 3 END
+    AND b.person_id <> ALL (g.path)
+)
+SELECT
+    c.person_id
+    , p.name
+    , c.depth
+    , c.movies
+FROM (
+    SELECT DISTINCT ON (g.person_id)
+        g.person_id
+        , g.depth
+        , g.movies
+    FROM graph g
+    WHERE g.depth > 0
+    ORDER BY g.person_id, g.depth, g.movies
+) c
+JOIN people p ON p.id = c.person_id
+ORDER BY c.depth, p.name
+`
+
+type QueryCollaboratorsParams struct {
+	Director string `db:"director" json:"director"`
+	Depth    uint64 `db:"depth" json:"depth"`
+}
+
+type QueryCollaboratorsRow struct {
+	PersonID int64    `db:"person_id" json:"person_id"`
+	Name     string   `db:"name" json:"name"`
+	Depth    int64    `db:"depth" json:"depth"`
+	Movies   []string `db:"movies" json:"movies"`
+}
+
+func (q *Queries) QueryCollaborators(ctx context.Context, arg QueryCollaboratorsParams) ([]QueryCollaboratorsRow, error) {
+	rows, err := q.db.Query(ctx, queryCollaborators, arg.Director, arg.Depth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryCollaboratorsRow
+	for rows.Next() {
+		var i QueryCollaboratorsRow
+		if err := rows.Scan(
+			&i.PersonID,
+			&i.Name,
+			&i.Depth,
+			&i.Movies,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryDetails = `-- name: QueryDetails :many
+SELECT
+    m.id
+    , m.title
+    , m.added_at
+    , m.rating
+    , m.runtime
+    , m.budget
+    , m.tagline
+    , m.metadata
+FROM movies m
+WHERE
+    (
+        $1::TEXT = ''
+        OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+        OR EXISTS (
+            SELECT 1
+            FROM movie_directors md
+            JOIN people p ON p.id = md.person_id
+            WHERE md.movie_id = m.id
+            AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+        )
+    )
+    AND ($2::INT8 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+    AND ($3::FLOAT8 = 0 OR m.rating >= $3)
+ORDER BY m.rating DESC
+LIMIT CASE WHEN $4::INT4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END
+`
+
+type QueryDetailsParams struct {
+	Search    string  `db:"search" json:"search"`
+	YearAdded int64   `db:"year_added" json:"year_added"`
+	MinRating float64 `db:"min_rating" json:"min_rating"`
+	Limit     uint64  `db:"limit" json:"limit"`
+}
+
+func (q *Queries) QueryDetails(ctx context.Context, arg QueryDetailsParams) ([]Movie, error) {
+	rows, err := q.db.Query(ctx, queryDetails,
+		arg.Search,
+		arg.YearAdded,
+		arg.MinRating,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Movie
+	for rows.Next() {
+		var i Movie
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.AddedAt,
+			&i.Rating,
+			&i.Runtime,
+			&i.Budget,
+			&i.Tagline,
+			&i.Metadata,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryDirectors = `-- name: QueryDirectors :many
 SELECT
     md.movie_id
@@ -123,6 +270,321 @@ func (q *Queries) QueryDirectors(ctx context.Context, dollar_1 []int64) ([]Query
 	return items, nil
 }
 
+const queryDirectorsUnnest = `-- name: QueryDirectorsUnnest :many
+SELECT
+    CAST(i.movie_id AS INT8) AS movie_id
+    , ARRAY_AGG(people.name ORDER BY people.name)::TEXT[] AS directors
+FROM unnest($1::INT8[]) WITH ORDINALITY AS i (movie_id, ord)
+JOIN movie_directors md ON md.movie_id = i.movie_id
+JOIN people ON people.id = md.person_id
+GROUP BY i.ord, i.movie_id
+ORDER BY i.ord
+`
+
+type QueryDirectorsUnnestRow struct {
+	MovieID   int64    `db:"movie_id" json:"movie_id"`
+	Directors []string `db:"directors" json:"directors"`
+}
+
+func (q *Queries) QueryDirectorsUnnest(ctx context.Context, dollar_1 []int64) ([]QueryDirectorsUnnestRow, error) {
+	rows, err := q.db.Query(ctx, queryDirectorsUnnest, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryDirectorsUnnestRow
+	for rows.Next() {
+		var i QueryDirectorsUnnestRow
+		if err := rows.Scan(&i.MovieID, &i.Directors); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryFacetCounts = `-- name: QueryFacetCounts :many
+SELECT
+    (CASE WHEN GROUPING(EXTRACT(YEAR FROM m.added_at)) = 0 THEN 'year' ELSE 'rating' END)::TEXT AS facet
+    , CAST(COALESCE(EXTRACT(YEAR FROM m.added_at), FLOOR(m.rating)) AS INT8) AS value
+    , COUNT(*) AS count
+FROM movies m
+WHERE
+    (
+        $1::TEXT = ''
+        OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+        OR EXISTS (
+            SELECT 1
+            FROM movie_directors md
+            JOIN people p ON p.id = md.person_id
+            WHERE md.movie_id = m.id
+            AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+        )
+    )
+    AND ($2::INT8 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+    AND ($3::FLOAT8 = 0 OR m.rating >= $3)
+GROUP BY GROUPING SETS ((EXTRACT(YEAR FROM m.added_at)), (FLOOR(m.rating)))
+ORDER BY facet, value
+`
+
+type QueryFacetCountsParams struct {
+	Search    string  `db:"search" json:"search"`
+	YearAdded int64   `db:"year_added" json:"year_added"`
+	MinRating float64 `db:"min_rating" json:"min_rating"`
+}
+
+type QueryFacetCountsRow struct {
+	Facet string `db:"facet" json:"facet"`
+	Value int64  `db:"value" json:"value"`
+	Count int64  `db:"count" json:"count"`
+}
+
+func (q *Queries) QueryFacetCounts(ctx context.Context, arg QueryFacetCountsParams) ([]QueryFacetCountsRow, error) {
+	rows, err := q.db.Query(ctx, queryFacetCounts, arg.Search, arg.YearAdded, arg.MinRating)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryFacetCountsRow
+	for rows.Next() {
+		var i QueryFacetCountsRow
+		if err := rows.Scan(&i.Facet, &i.Value, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryFacetsPage = `-- name: QueryFacetsPage :many
+SELECT
+    m.id
+    , m.title
+    , m.added_at
+    , m.rating
+FROM movies m
+WHERE
+    (
+        $1::TEXT = ''
+        OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+        OR EXISTS (
+            SELECT 1
+            FROM movie_directors md
+            JOIN people p ON p.id = md.person_id
+            WHERE md.movie_id = m.id
+            AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+        )
+    )
+    AND ($2::INT8 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+    AND ($3::FLOAT8 = 0 OR m.rating >= $3)
+ORDER BY
+    CASE WHEN $4::TEXT = 'title' AND NOT $5::BOOL THEN m.title END ASC
+    , CASE WHEN $4::TEXT = 'title' AND $5::BOOL THEN m.title END DESC
+    , CASE WHEN $4::TEXT = 'added_at' AND NOT $5::BOOL THEN m.added_at END ASC
+    , CASE WHEN $4::TEXT = 'added_at' AND $5::BOOL THEN m.added_at END DESC
+    , CASE WHEN $4::TEXT = 'rating' AND NOT $5::BOOL THEN m.rating END ASC
+    , CASE WHEN $4::TEXT = 'rating' AND $5::BOOL THEN m.rating END DESC
+LIMIT CASE WHEN $6::INT4 BETWEEN 1 AND 1000 THEN $6 ELSE 1000 END
+`
+
+type QueryFacetsPageParams struct {
+	Search     string  `db:"search" json:"search"`
+	YearAdded  int64   `db:"year_added" json:"year_added"`
+	MinRating  float64 `db:"min_rating" json:"min_rating"`
+	Sort       string  `db:"sort" json:"sort"`
+	Descending bool    `db:"descending" json:"descending"`
+	Limit      uint64  `db:"limit" json:"limit"`
+}
+
+type QueryFacetsPageRow struct {
+	ID      int64     `db:"id" json:"id"`
+	Title   string    `db:"title" json:"title"`
+	AddedAt time.Time `db:"added_at" json:"added_at"`
+	Rating  float64   `db:"rating" json:"rating"`
+}
+
+func (q *Queries) QueryFacetsPage(ctx context.Context, arg QueryFacetsPageParams) ([]QueryFacetsPageRow, error) {
+	rows, err := q.db.Query(ctx, queryFacetsPage,
+		arg.Search,
+		arg.YearAdded,
+		arg.MinRating,
+		arg.Sort,
+		arg.Descending,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryFacetsPageRow
+	for rows.Next() {
+		var i QueryFacetsPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.AddedAt,
+			&i.Rating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryJSON = `-- name: QueryJSON :many
+SELECT
+    m.id
+    , m.title
+    , m.added_at
+    , m.rating
+    , COALESCE(d.directors, '[]')::JSON AS directors
+FROM movies m
+LEFT JOIN LATERAL (
+    SELECT json_agg(json_build_object('id', p.id, 'name', p.name) ORDER BY p.name) AS directors
+    FROM movie_directors md
+    JOIN people p ON p.id = md.person_id
+    WHERE md.movie_id = m.id
+) d ON true
+WHERE
+    (
+        sqlc.narg(search)::TEXT = ''
+        OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', sqlc.narg(search))
+        OR EXISTS (
+            SELECT 1
+            FROM movie_directors md
+            JOIN people p ON p.id = md.person_id
+            WHERE md.movie_id = m.id
+            AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', sqlc.narg(search))
+        )
+    )
+    AND (sqlc.narg(year_added)::INT8 = 0 OR EXTRACT(YEAR FROM m.added_at) = sqlc.narg(year_added))
+    AND (sqlc.narg(min_rating)::FLOAT8 = 0 OR m.rating >= sqlc.narg(min_rating))
+ORDER BY m.rating DESC
+LIMIT CASE WHEN sqlc.narg('limit')::INT4 BETWEEN 1 AND 1000 THEN sqlc.narg('limit') ELSE 1000 END
+`
+
+type QueryJSONParams struct {
+	Search    string  `db:"search" json:"search"`
+	YearAdded int64   `db:"year_added" json:"year_added"`
+	MinRating float64 `db:"min_rating" json:"min_rating"`
+	Limit     uint64  `db:"limit" json:"limit"`
+}
+
+type QueryJSONRow struct {
+	ID        int64     `db:"id" json:"id"`
+	Title     string    `db:"title" json:"title"`
+	AddedAt   time.Time `db:"added_at" json:"added_at"`
+	Rating    float64   `db:"rating" json:"rating"`
+	Directors []byte    `db:"directors" json:"directors"`
+}
+
+func (q *Queries) QueryJSON(ctx context.Context, arg QueryJSONParams) ([]QueryJSONRow, error) {
+	rows, err := q.db.Query(ctx, queryJSON,
+		arg.Search,
+		arg.YearAdded,
+		arg.MinRating,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryJSONRow
+	for rows.Next() {
+		var i QueryJSONRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.AddedAt,
+			&i.Rating,
+			&i.Directors,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryMovie = `-- name: QueryMovie :one
+SELECT
+    m.id
+    , m.title
+    , m.added_at
+    , m.rating
+    , d.directors
+FROM movies m
+LEFT JOIN LATERAL (
+    SELECT ARRAY_AGG(p.name ORDER BY p.name) AS directors
+    FROM movie_directors md
+    JOIN people p ON p.id = md.person_id
+    WHERE md.movie_id = m.id
+) d ON true
+WHERE m.id = $1::INT8
+`
+
+type QueryMovieRow struct {
+	ID        int64     `db:"id" json:"id"`
+	Title     string    `db:"title" json:"title"`
+	AddedAt   time.Time `db:"added_at" json:"added_at"`
+	Rating    float64   `db:"rating" json:"rating"`
+	Directors []string  `db:"directors" json:"directors"`
+}
+
+func (q *Queries) QueryMovie(ctx context.Context, id int64) (QueryMovieRow, error) {
+	row := q.db.QueryRow(ctx, queryMovie, id)
+	var i QueryMovieRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.AddedAt,
+		&i.Rating,
+		&i.Directors,
+	)
+	return i, err
+}
+
+const queryMovieDirectors = `-- name: QueryMovieDirectors :many
+SELECT people.name
+FROM movie_directors md
+JOIN people ON people.id = md.person_id
+WHERE md.movie_id = $1
+ORDER BY people.name
+`
+
+func (q *Queries) QueryMovieDirectors(ctx context.Context, movieID int64) ([]string, error) {
+	rows, err := q.db.Query(ctx, queryMovieDirectors, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryPreload = `-- name: QueryPreload :many
 SELECT
     id
@@ -155,7 +617,14 @@ type QueryPreloadParams struct {
 	Limit     uint64  `db:"limit" json:"limit"`
 }
 
-func (q *Queries) QueryPreload(ctx context.Context, arg QueryPreloadParams) ([]Movie, error) {
+type QueryPreloadRow struct {
+	ID      int64     `db:"id" json:"id"`
+	Title   string    `db:"title" json:"title"`
+	AddedAt time.Time `db:"added_at" json:"added_at"`
+	Rating  float64   `db:"rating" json:"rating"`
+}
+
+func (q *Queries) QueryPreload(ctx context.Context, arg QueryPreloadParams) ([]QueryPreloadRow, error) {
 	rows, err := q.db.Query(ctx, queryPreload,
 		arg.Search,
 		arg.YearAdded,
@@ -166,14 +635,342 @@ func (q *Queries) QueryPreload(ctx context.Context, arg QueryPreloadParams) ([]M
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Movie
+	var items []QueryPreloadRow
 	for rows.Next() {
-		var i Movie
+		var i QueryPreloadRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.AddedAt,
+			&i.Rating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const querySearch = `-- name: QuerySearch :many
+SELECT
+    m.id
+    , m.title
+    , m.added_at
+    , m.rating
+    , d.directors::TEXT[] AS directors
+    , ts_rank(
+        setweight(to_tsvector('simple', m.title), 'A')
+        || setweight(to_tsvector('simple', COALESCE(d.names, '')), 'B'),
+        q.query
+    )::FLOAT8 AS rank
+    , ts_headline('simple', m.title || ' / ' || COALESCE(d.names, ''), q.query)::TEXT AS headline
+FROM movies m
+CROSS JOIN LATERAL (
+    SELECT CASE
+        WHEN $1::BOOL THEN websearch_to_tsquery('simple', $2::TEXT)
+        ELSE plainto_tsquery('simple', $2::TEXT)
+    END AS query
+) q
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(p.name ORDER BY p.name) AS directors
+        , STRING_AGG(p.name, ' ' ORDER BY p.name) AS names
+    FROM movie_directors md
+    JOIN people p ON p.id = md.person_id
+    WHERE md.movie_id = m.id
+) d ON true
+WHERE
+    to_tsvector('simple', m.title) @@ q.query
+    OR EXISTS (
+        SELECT 1
+        FROM movie_directors md
+        JOIN people p ON p.id = md.person_id
+        WHERE md.movie_id = m.id
+        AND to_tsvector('simple', p.name) @@ q.query
+    )
+ORDER BY rank DESC, m.id
+LIMIT CASE WHEN $3::INT4 BETWEEN 1 AND 1000 THEN $3 ELSE 1000 END
+`
+
+type QuerySearchParams struct {
+	Websearch bool   `db:"websearch" json:"websearch"`
+	Search    string `db:"search" json:"search"`
+	Limit     uint64 `db:"limit" json:"limit"`
+}
+
+type QuerySearchRow struct {
+	ID        int64     `db:"id" json:"id"`
+	Title     string    `db:"title" json:"title"`
+	AddedAt   time.Time `db:"added_at" json:"added_at"`
+	Rating    float64   `db:"rating" json:"rating"`
+	Directors []string  `db:"directors" json:"directors"`
+	Rank      float64   `db:"rank" json:"rank"`
+	Headline  string    `db:"headline" json:"headline"`
+}
+
+func (q *Queries) QuerySearch(ctx context.Context, arg QuerySearchParams) ([]QuerySearchRow, error) {
+	rows, err := q.db.Query(ctx, querySearch, arg.Websearch, arg.Search, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QuerySearchRow
+	for rows.Next() {
+		var i QuerySearchRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
 			&i.AddedAt,
 			&i.Rating,
+			&i.Directors,
+			&i.Rank,
+			&i.Headline,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryTopRated = `-- name: QueryTopRated :many
+WITH director_ratings AS (
+    SELECT
+        md.person_id
+        , CAST(AVG(m.rating) AS FLOAT8) AS avg_rating
+    FROM movie_directors md
+    JOIN movies m ON m.id = md.movie_id
+    GROUP BY md.person_id
+), ranked AS (
+    SELECT
+        m.id
+        , m.title
+        , m.added_at
+        , m.rating
+        , CAST(EXTRACT(YEAR FROM m.added_at) AS INT8) AS year
+        , ROW_NUMBER() OVER (PARTITION BY EXTRACT(YEAR FROM m.added_at) ORDER BY m.rating DESC, m.id) AS position
+    FROM movies m
+    WHERE $1::FLOAT8 = 0 OR m.rating >= $1
+)
+SELECT
+    r.id
+    , r.title
+    , r.added_at
+    , r.rating
+    , r.year
+    , r.position
+    , d.directors::TEXT[] AS directors
+    , d.ratings::FLOAT8[] AS ratings
+FROM ranked r
+LEFT JOIN LATERAL (
+    SELECT
+        ARRAY_AGG(p.name ORDER BY p.name) AS directors
+        , ARRAY_AGG(dr.avg_rating ORDER BY p.name) AS ratings
+    FROM movie_directors md
+    JOIN people p ON p.id = md.person_id
+    JOIN director_ratings dr ON dr.person_id = md.person_id
+    WHERE md.movie_id = r.id
+) d ON true
+WHERE r.position <= CASE WHEN $2::INT4 BETWEEN 1 AND 10 THEN $2 ELSE 10 END
+ORDER BY r.year, r.position
+`
+
+type QueryTopRatedParams struct {
+	MinRating float64 `db:"min_rating" json:"min_rating"`
+	PerYear   uint64  `db:"per_year" json:"per_year"`
+}
+
+type QueryTopRatedRow struct {
+	ID        int64     `db:"id" json:"id"`
+	Title     string    `db:"title" json:"title"`
+	AddedAt   time.Time `db:"added_at" json:"added_at"`
+	Rating    float64   `db:"rating" json:"rating"`
+	Year      int64     `db:"year" json:"year"`
+	Position  int64     `db:"position" json:"position"`
+	Directors []string  `db:"directors" json:"directors"`
+	Ratings   []float64 `db:"ratings" json:"ratings"`
+}
+
+func (q *Queries) QueryTopRated(ctx context.Context, arg QueryTopRatedParams) ([]QueryTopRatedRow, error) {
+	rows, err := q.db.Query(ctx, queryTopRated, arg.MinRating, arg.PerYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryTopRatedRow
+	for rows.Next() {
+		var i QueryTopRatedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.AddedAt,
+			&i.Rating,
+			&i.Year,
+			&i.Position,
+			&i.Directors,
+			&i.Ratings,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryWide = `-- name: QueryWide :many
+SELECT
+    id
+    , int01
+    , int02
+    , int03
+    , int04
+    , int05
+    , int06
+    , int07
+    , int08
+    , int09
+    , int10
+    , int11
+    , int12
+    , num01
+    , num02
+    , num03
+    , num04
+    , num05
+    , num06
+    , num07
+    , num08
+    , num09
+    , num10
+    , num11
+    , num12
+    , time01
+    , time02
+    , time03
+    , time04
+    , time05
+    , time06
+    , time07
+    , time08
+    , time09
+    , time10
+    , time11
+    , time12
+    , tags01
+    , tags02
+    , tags03
+    , tags04
+    , tags05
+    , tags06
+    , tags07
+    , tags08
+    , tags09
+    , tags10
+    , tags11
+    , tags12
+    , note01
+    , note02
+    , note03
+    , note04
+    , note05
+    , note06
+    , note07
+    , note08
+    , note09
+    , note10
+    , note11
+    , note12
+FROM wide_rows
+WHERE id > $1::INT8
+ORDER BY id
+LIMIT CASE WHEN $2::INT4 BETWEEN 1 AND 1000 THEN $2 ELSE 1000 END
+`
+
+type QueryWideParams struct {
+	After int64  `db:"after" json:"after"`
+	Limit uint64 `db:"limit" json:"limit"`
+}
+
+func (q *Queries) QueryWide(ctx context.Context, arg QueryWideParams) ([]WideRow, error) {
+	rows, err := q.db.Query(ctx, queryWide, arg.After, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WideRow
+	for rows.Next() {
+		var i WideRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Int01,
+			&i.Int02,
+			&i.Int03,
+			&i.Int04,
+			&i.Int05,
+			&i.Int06,
+			&i.Int07,
+			&i.Int08,
+			&i.Int09,
+			&i.Int10,
+			&i.Int11,
+			&i.Int12,
+			&i.Num01,
+			&i.Num02,
+			&i.Num03,
+			&i.Num04,
+			&i.Num05,
+			&i.Num06,
+			&i.Num07,
+			&i.Num08,
+			&i.Num09,
+			&i.Num10,
+			&i.Num11,
+			&i.Num12,
+			&i.Time01,
+			&i.Time02,
+			&i.Time03,
+			&i.Time04,
+			&i.Time05,
+			&i.Time06,
+			&i.Time07,
+			&i.Time08,
+			&i.Time09,
+			&i.Time10,
+			&i.Time11,
+			&i.Time12,
+			&i.Tags01,
+			&i.Tags02,
+			&i.Tags03,
+			&i.Tags04,
+			&i.Tags05,
+			&i.Tags06,
+			&i.Tags07,
+			&i.Tags08,
+			&i.Tags09,
+			&i.Tags10,
+			&i.Tags11,
+			&i.Tags12,
+			&i.Note01,
+			&i.Note02,
+			&i.Note03,
+			&i.Note04,
+			&i.Note05,
+			&i.Note06,
+			&i.Note07,
+			&i.Note08,
+			&i.Note09,
+			&i.Note10,
+			&i.Note11,
+			&i.Note12,
 		); err != nil {
 			return nil, err
 		}
@@ -3,6 +3,10 @@ CREATE TABLE IF NOT EXISTS movies (
     , title TEXT NOT NULL
     , added_at DATE NOT NULL
     , rating NUMERIC NOT NULL
+    , runtime INTEGER
+    , budget BIGINT
+    , tagline TEXT
+    , metadata JSONB
 );
 
 CREATE TABLE IF NOT EXISTS people (
@@ -15,3 +19,67 @@ CREATE TABLE IF NOT EXISTS movie_directors (
     , person_id INTEGER REFERENCES people (id) ON DELETE CASCADE
     , PRIMARY KEY (movie_id, person_id)
 );
+
+CREATE TABLE IF NOT EXISTS wide_rows (
+    id BIGINT PRIMARY KEY
+    , int01 BIGINT NOT NULL
+    , int02 BIGINT NOT NULL
+    , int03 BIGINT NOT NULL
+    , int04 BIGINT NOT NULL
+    , int05 BIGINT NOT NULL
+    , int06 BIGINT NOT NULL
+    , int07 BIGINT NOT NULL
+    , int08 BIGINT NOT NULL
+    , int09 BIGINT NOT NULL
+    , int10 BIGINT NOT NULL
+    , int11 BIGINT NOT NULL
+    , int12 BIGINT NOT NULL
+    , num01 NUMERIC NOT NULL
+    , num02 NUMERIC NOT NULL
+    , num03 NUMERIC NOT NULL
+    , num04 NUMERIC NOT NULL
+    , num05 NUMERIC NOT NULL
+    , num06 NUMERIC NOT NULL
+    , num07 NUMERIC NOT NULL
+    , num08 NUMERIC NOT NULL
+    , num09 NUMERIC NOT NULL
+    , num10 NUMERIC NOT NULL
+    , num11 NUMERIC NOT NULL
+    , num12 NUMERIC NOT NULL
+    , time01 TIMESTAMPTZ NOT NULL
+    , time02 TIMESTAMPTZ NOT NULL
+    , time03 TIMESTAMPTZ NOT NULL
+    , time04 TIMESTAMPTZ NOT NULL
+    , time05 TIMESTAMPTZ NOT NULL
+    , time06 TIMESTAMPTZ NOT NULL
+    , time07 TIMESTAMPTZ NOT NULL
+    , time08 TIMESTAMPTZ NOT NULL
+    , time09 TIMESTAMPTZ NOT NULL
+    , time10 TIMESTAMPTZ NOT NULL
+    , time11 TIMESTAMPTZ NOT NULL
+    , time12 TIMESTAMPTZ NOT NULL
+    , tags01 TEXT[] NOT NULL
+    , tags02 TEXT[] NOT NULL
+    , tags03 TEXT[] NOT NULL
+    , tags04 TEXT[] NOT NULL
+    , tags05 TEXT[] NOT NULL
+    , tags06 TEXT[] NOT NULL
+    , tags07 TEXT[] NOT NULL
+    , tags08 TEXT[] NOT NULL
+    , tags09 TEXT[] NOT NULL
+    , tags10 TEXT[] NOT NULL
+    , tags11 TEXT[] NOT NULL
+    , tags12 TEXT[] NOT NULL
+    , note01 TEXT
+    , note02 TEXT
+    , note03 TEXT
+    , note04 TEXT
+    , note05 TEXT
+    , note06 TEXT
+    , note07 TEXT
+    , note08 TEXT
+    , note09 TEXT
+    , note10 TEXT
+    , note11 TEXT
+    , note12 TEXT
+);
@@ -2,28 +2,54 @@ package sqlcflix
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/go-sqlt/benchflix"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewRepository(conn string, min, max int, idle time.Duration) benchflix.Repository {
-	cfg := benchflix.Must(pgxpool.ParseConfig(conn))
+func init() {
+	benchflix.RegisterAdapter("sqlc", Open)
+}
+
+func NewRepository(conn string, min, max int, idle time.Duration, opts ...benchflix.Option) benchflix.Repository {
+	return benchflix.Must(Open(conn, min, max, idle, opts...))
+}
+
+// Open is NewRepository returning errors.
+func Open(conn string, min, max int, idle time.Duration, opts ...benchflix.Option) (benchflix.Repository, error) {
+	cfg, err := pgxpool.ParseConfig(conn)
+	if err != nil {
+		return nil, err
+	}
 
 	cfg.MaxConns = int32(max)
 	cfg.MinConns = int32(min)
 	cfg.MaxConnIdleTime = idle
 
-	pool := benchflix.Must(pgxpool.NewWithConfig(context.Background(), cfg))
+	if benchflix.NewOptions(opts...).Instrument {
+		benchflix.InstrumentPgx(cfg.ConnConfig)
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	return Repository{
+		Pool:    pool,
 		Queries: New(pool),
-	}
+	}, nil
 }
 
 type Repository struct {
+	Pool    *pgxpool.Pool
 	Queries *Queries
+	Loader  *benchflix.Loader[int64, []string]
 }
 
 func (r Repository) QueryList(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
@@ -35,7 +61,13 @@ func (r Repository) QueryList(ctx context.Context, params benchflix.ListParams)
 	movies := make([]benchflix.Movie, len(rows))
 
 	for i, row := range rows {
-		movies[i] = benchflix.Movie(row)
+		movies[i] = benchflix.Movie{
+			ID:        row.ID,
+			Title:     row.Title,
+			AddedAt:   row.AddedAt,
+			Rating:    row.Rating,
+			Directors: row.Directors,
+		}
 	}
 
 	return movies, nil
@@ -69,6 +101,19 @@ func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListP
 		return movies, nil
 	}
 
+	if r.Loader != nil {
+		directors, err := r.Loader.LoadMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, d := range directors {
+			movies[idMap[id]].Directors = d
+		}
+
+		return movies, nil
+	}
+
 	movieDirectors, err := r.Queries.QueryDirectors(ctx, ids)
 	if err != nil {
 		return nil, err
@@ -81,6 +126,65 @@ func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListP
 	return movies, nil
 }
 
+func (r Repository) QueryListNPlusOne(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.Queries.QueryPreload(ctx, QueryPreloadParams(params))
+	if err != nil {
+		return nil, err
+	}
+
+	movies := make([]benchflix.Movie, len(rows))
+
+	for i, row := range rows {
+		movies[i] = benchflix.Movie{
+			ID:      row.ID,
+			Title:   row.Title,
+			AddedAt: row.AddedAt,
+			Rating:  row.Rating,
+		}
+
+		movies[i].Directors, err = r.Queries.QueryMovieDirectors(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QueryListJSON(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.Queries.QueryJSON(ctx, QueryJSONParams(params))
+	if err != nil {
+		return nil, err
+	}
+
+	movies := make([]benchflix.Movie, len(rows))
+
+	for i, row := range rows {
+		var people []benchflix.Person
+
+		if err := json.Unmarshal(row.Directors, &people); err != nil {
+			return nil, err
+		}
+
+		movies[i] = benchflix.Movie{
+			ID:      row.ID,
+			Title:   row.Title,
+			AddedAt: row.AddedAt,
+			Rating:  row.Rating,
+		}
+
+		if len(people) > 0 {
+			movies[i].Directors = make([]string, len(people))
+
+			for j, p := range people {
+				movies[i].Directors[j] = p.Name
+			}
+		}
+	}
+
+	return movies, nil
+}
+
 //nolint:maintidx
 func (r Repository) QueryDashboard(ctx context.Context, params benchflix.DashboardParams) ([]benchflix.Movie, error) {
 	return nil, benchflix.ErrSkip
@@ -90,3 +194,357 @@ func (r Repository) QueryDashboard(ctx context.Context, params benchflix.Dashboa
 func (r Repository) QueryDashboardPreload(ctx context.Context, params benchflix.DashboardParams) ([]benchflix.Movie, error) {
 	return nil, benchflix.ErrSkip
 }
+
+func (r Repository) QueryDetails(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.Queries.QueryDetails(ctx, QueryDetailsParams(params))
+	if err != nil {
+		return nil, err
+	}
+
+	movies := make([]benchflix.Movie, len(rows))
+
+	for i, row := range rows {
+		movies[i] = benchflix.Movie{
+			ID:       row.ID,
+			Title:    row.Title,
+			AddedAt:  row.AddedAt,
+			Rating:   row.Rating,
+			Runtime:  row.Runtime,
+			Budget:   row.Budget,
+			Tagline:  row.Tagline,
+			Metadata: row.Metadata,
+		}
+	}
+
+	return movies, nil
+}
+
+// QueryDetailsNull is not supported: sqlc maps every column to a single Go type,
+// set by the overrides in sqlc.yaml, so the generated Movie already scans the
+// nullable columns into pointers. Null types would need a second generated package.
+func (r Repository) QueryDetailsNull(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	return nil, benchflix.ErrSkip
+}
+
+func (r Repository) QuerySearch(ctx context.Context, params benchflix.SearchParams) ([]benchflix.SearchResult, error) {
+	rows, err := r.Queries.QuerySearch(ctx, QuerySearchParams{
+		Websearch: params.Websearch,
+		Search:    params.Search,
+		Limit:     params.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]benchflix.SearchResult, len(rows))
+
+	for i, row := range rows {
+		results[i] = benchflix.SearchResult{
+			Movie: benchflix.Movie{
+				ID:        row.ID,
+				Title:     row.Title,
+				AddedAt:   row.AddedAt,
+				Rating:    row.Rating,
+				Directors: row.Directors,
+			},
+			Rank:     row.Rank,
+			Headline: row.Headline,
+		}
+	}
+
+	return results, nil
+}
+
+func (r Repository) QueryFacets(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	var result benchflix.FacetResult
+
+	switch params.Sort {
+	case "title", "added_at", "rating":
+	default:
+		return result, fmt.Errorf("invalid sort")
+	}
+
+	rows, err := r.Queries.QueryFacetsPage(ctx, QueryFacetsPageParams{
+		Search:     params.Search,
+		YearAdded:  params.YearAdded,
+		MinRating:  params.MinRating,
+		Sort:       params.Sort,
+		Descending: params.Desc,
+		Limit:      params.Limit,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	var (
+		ids   = make([]int64, len(rows))
+		idMap = make(map[int64]int, len(rows))
+	)
+
+	result.Movies = make([]benchflix.Movie, len(rows))
+
+	for i, row := range rows {
+		result.Movies[i] = benchflix.Movie{
+			ID:      row.ID,
+			Title:   row.Title,
+			AddedAt: row.AddedAt,
+			Rating:  row.Rating,
+		}
+
+		ids[i] = row.ID
+		idMap[row.ID] = i
+	}
+
+	if params.WithDirectors && len(ids) > 0 {
+		movieDirectors, err := r.Queries.QueryDirectors(ctx, ids)
+		if err != nil {
+			return result, err
+		}
+
+		for _, md := range movieDirectors {
+			result.Movies[idMap[md.MovieID]].Directors = md.Directors
+		}
+	}
+
+	counts, err := r.Queries.QueryFacetCounts(ctx, QueryFacetCountsParams{
+		Search:    params.Search,
+		YearAdded: params.YearAdded,
+		MinRating: params.MinRating,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	for _, c := range counts {
+		if c.Facet == "year" {
+			result.Years = append(result.Years, benchflix.Facet{Value: c.Value, Count: c.Count})
+		} else {
+			result.Ratings = append(result.Ratings, benchflix.Facet{Value: c.Value, Count: c.Count})
+		}
+	}
+
+	return result, nil
+}
+
+// QueryFacetsBatch sends the generated page and count queries in a single
+// round trip. sqlc only batches repeated calls of one query, so the batch is
+// assembled by hand from the generated statements.
+func (r Repository) QueryFacetsBatch(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	var (
+		result benchflix.FacetResult
+		ids    = make([]int64, 0, params.Limit)
+		idMap  = make(map[int64]int, params.Limit)
+		batch  = &pgx.Batch{}
+	)
+
+	switch params.Sort {
+	case "title", "added_at", "rating":
+	default:
+		return result, fmt.Errorf("invalid sort")
+	}
+
+	batch.Queue(queryFacetsPage,
+		params.Search,
+		params.YearAdded,
+		params.MinRating,
+		params.Sort,
+		params.Desc,
+		params.Limit,
+	).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			var movie benchflix.Movie
+
+			if err := rows.Scan(&movie.ID, &movie.Title, &movie.AddedAt, &movie.Rating); err != nil {
+				return err
+			}
+
+			idMap[movie.ID] = len(ids)
+			ids = append(ids, movie.ID)
+			result.Movies = append(result.Movies, movie)
+		}
+
+		return rows.Err()
+	})
+
+	batch.Queue(queryFacetCounts, params.Search, params.YearAdded, params.MinRating).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			var c QueryFacetCountsRow
+
+			if err := rows.Scan(&c.Facet, &c.Value, &c.Count); err != nil {
+				return err
+			}
+
+			if c.Facet == "year" {
+				result.Years = append(result.Years, benchflix.Facet{Value: c.Value, Count: c.Count})
+			} else {
+				result.Ratings = append(result.Ratings, benchflix.Facet{Value: c.Value, Count: c.Count})
+			}
+		}
+
+		return rows.Err()
+	})
+
+	if err := r.Queries.db.SendBatch(ctx, batch).Close(); err != nil {
+		return result, err
+	}
+
+	if !params.WithDirectors || len(ids) == 0 {
+		return result, nil
+	}
+
+	movieDirectors, err := r.Queries.QueryDirectors(ctx, ids)
+	if err != nil {
+		return result, err
+	}
+
+	for _, md := range movieDirectors {
+		result.Movies[idMap[md.MovieID]].Directors = md.Directors
+	}
+
+	return result, nil
+}
+
+func (r Repository) QueryTopRated(ctx context.Context, params benchflix.TopParams) ([]benchflix.TopMovie, error) {
+	rows, err := r.Queries.QueryTopRated(ctx, QueryTopRatedParams(params))
+	if err != nil {
+		return nil, err
+	}
+
+	movies := make([]benchflix.TopMovie, len(rows))
+
+	for i, row := range rows {
+		movies[i] = benchflix.TopMovie{
+			Movie: benchflix.Movie{
+				ID:        row.ID,
+				Title:     row.Title,
+				AddedAt:   row.AddedAt,
+				Rating:    row.Rating,
+				Directors: row.Directors,
+			},
+			Year:            row.Year,
+			Position:        row.Position,
+			DirectorRatings: row.Ratings,
+		}
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QueryCollaborators(ctx context.Context, params benchflix.GraphParams) ([]benchflix.Collaborator, error) {
+	rows, err := r.Queries.QueryCollaborators(ctx, QueryCollaboratorsParams(params))
+	if err != nil {
+		return nil, err
+	}
+
+	collaborators := make([]benchflix.Collaborator, len(rows))
+
+	for i, row := range rows {
+		collaborators[i] = benchflix.Collaborator{
+			ID:     row.PersonID,
+			Name:   row.Name,
+			Depth:  row.Depth,
+			Movies: row.Movies,
+		}
+	}
+
+	return collaborators, nil
+}
+
+func (r Repository) QueryMovie(ctx context.Context, id int64) (benchflix.Movie, error) {
+	row, err := r.Queries.QueryMovie(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return benchflix.Movie{}, benchflix.ErrNotFound
+	}
+
+	if err != nil {
+		return benchflix.Movie{}, err
+	}
+
+	return benchflix.Movie{
+		ID:        row.ID,
+		Title:     row.Title,
+		AddedAt:   row.AddedAt,
+		Rating:    row.Rating,
+		Directors: row.Directors,
+	}, nil
+}
+
+func (r Repository) QueryWide(ctx context.Context, params benchflix.WideParams) ([]benchflix.WideRow, error) {
+	rows, err := r.Queries.QueryWide(ctx, QueryWideParams(params))
+	if err != nil {
+		return nil, err
+	}
+
+	wide := make([]benchflix.WideRow, len(rows))
+
+	for i, row := range rows {
+		wide[i] = benchflix.WideRow(row)
+	}
+
+	return wide, nil
+}
+
+func (r Repository) QueryDirectors(ctx context.Context, params benchflix.PreloadParams) ([]benchflix.MovieDirectors, error) {
+	var movieDirectors = make([]benchflix.MovieDirectors, 0, len(params.IDs))
+
+	switch params.Strategy {
+	case benchflix.PreloadAny:
+		rows, err := r.Queries.QueryDirectors(ctx, params.IDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			movieDirectors = append(movieDirectors, benchflix.MovieDirectors(row))
+		}
+	case benchflix.PreloadUnnest:
+		rows, err := r.Queries.QueryDirectorsUnnest(ctx, params.IDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			movieDirectors = append(movieDirectors, benchflix.MovieDirectors(row))
+		}
+	case benchflix.PreloadBatch:
+		var batchErr error
+
+		r.Queries.QueryDirectorsBatch(ctx, params.IDs).Query(func(_ int, rows []QueryDirectorsBatchRow, err error) {
+			if err != nil {
+				batchErr = err
+
+				return
+			}
+
+			for _, row := range rows {
+				movieDirectors = append(movieDirectors, benchflix.MovieDirectors(row))
+			}
+		})
+
+		if batchErr != nil {
+			return nil, batchErr
+		}
+	case benchflix.PreloadTempTable:
+		return nil, benchflix.ErrSkip
+	default:
+		return nil, fmt.Errorf("invalid strategy: %s", params.Strategy)
+	}
+
+	return movieDirectors, nil
+}
+
+func (r Repository) WithLoader(wait time.Duration, maxBatch int) benchflix.Repository {
+	r.Loader = benchflix.NewDirectorsLoader(wait, maxBatch, r.QueryDirectors)
+
+	return r
+}
+
+func (r Repository) PoolStats() benchflix.PoolStats {
+	return benchflix.PgxPoolStats(r.Pool)
+}
+
+func (r Repository) Close() error {
+	r.Pool.Close()
+
+	return nil
+}
@@ -3,16 +3,33 @@ package sqlflix
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/go-sqlt/benchflix"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/lib/pq"
 )
 
-func NewRepository(conn string, min, max int, idle time.Duration) benchflix.Repository {
-	db := benchflix.Must(sql.Open("pgx", conn))
+func init() {
+	benchflix.RegisterAdapter("sql", Open)
+}
+
+func NewRepository(conn string, min, max int, idle time.Duration, opts ...benchflix.Option) benchflix.Repository {
+	return benchflix.Must(Open(conn, min, max, idle, opts...))
+}
+
+// Open is NewRepository returning errors.
+func Open(conn string, min, max int, idle time.Duration, opts ...benchflix.Option) (benchflix.Repository, error) {
+	connector, err := benchflix.NewOptions(opts...).Connector(stdlib.GetDefaultDriver(), conn)
+	if err != nil {
+		return nil, err
+	}
+
+	db := sql.OpenDB(connector)
 
 	db.SetMaxOpenConns(max)
 	db.SetMaxIdleConns(min)
@@ -20,11 +37,12 @@ func NewRepository(conn string, min, max int, idle time.Duration) benchflix.Repo
 
 	return Repository{
 		DB: db,
-	}
+	}, nil
 }
 
 type Repository struct {
-	DB *sql.DB
+	DB     *sql.DB
+	Loader *benchflix.Loader[int64, []string]
 }
 
 func (r Repository) QueryList(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
@@ -152,6 +170,19 @@ func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListP
 		return movies, nil
 	}
 
+	if r.Loader != nil {
+		directors, err := r.Loader.LoadMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, d := range directors {
+			movies[idMap[id]].Directors = d
+		}
+
+		return movies, nil
+	}
+
 	dirRows, err := r.DB.QueryContext(ctx, `
 		SELECT md.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
 		FROM movie_directors md
@@ -185,6 +216,166 @@ func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListP
 	return movies, nil
 }
 
+func (r Repository) QueryListNPlusOne(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	movies := make([]benchflix.Movie, 0, params.Limit)
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+		FROM movies m
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3 = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`, params.Search, params.YearAdded, params.MinRating, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var movie benchflix.Movie
+
+		if err := rows.Scan(&movie.ID, &movie.Title, &movie.AddedAt, &movie.Rating); err != nil {
+			return nil, err
+		}
+
+		movies = append(movies, movie)
+	}
+
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range movies {
+		if err := r.queryMovieDirectors(ctx, &movies[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QueryListJSON(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, COALESCE(d.directors, '[]') AS directors
+		FROM movies m
+		LEFT JOIN LATERAL (
+			SELECT json_agg(json_build_object('id', p.id, 'name', p.name) ORDER BY p.name) AS directors
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+		) d ON true
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3 = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`, params.Search, params.YearAdded, params.MinRating, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	movies := make([]benchflix.Movie, 0, params.Limit)
+
+	for rows.Next() {
+		var (
+			movie  benchflix.Movie
+			data   []byte
+			people []benchflix.Person
+		)
+
+		if err := rows.Scan(&movie.ID, &movie.Title, &movie.AddedAt, &movie.Rating, &data); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(data, &people); err != nil {
+			return nil, err
+		}
+
+		if len(people) > 0 {
+			movie.Directors = make([]string, len(people))
+
+			for i, p := range people {
+				movie.Directors[i] = p.Name
+			}
+		}
+
+		movies = append(movies, movie)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+func (r Repository) queryMovieDirectors(ctx context.Context, movie *benchflix.Movie) error {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT people.name
+		FROM movie_directors md
+		JOIN people ON people.id = md.person_id
+		WHERE md.movie_id = $1
+		ORDER BY people.name;
+	`, movie.ID)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+
+		movie.Directors = append(movie.Directors, name)
+	}
+
+	return rows.Err()
+}
+
 func (r Repository) QueryDashboard(ctx context.Context, params benchflix.DashboardParams) ([]benchflix.Movie, error) {
 	var (
 		movies     = make([]benchflix.Movie, 0, params.Limit)
@@ -394,6 +585,19 @@ func (r Repository) QueryDashboardPreload(ctx context.Context, params benchflix.
 		return movies, nil
 	}
 
+	if r.Loader != nil {
+		directors, err := r.Loader.LoadMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, d := range directors {
+			movies[idMap[id]].Directors = d
+		}
+
+		return movies, nil
+	}
+
 	dirRows, err := r.DB.QueryContext(ctx, `
 		SELECT md.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
 		FROM movie_directors md
@@ -426,3 +630,712 @@ func (r Repository) QueryDashboardPreload(ctx context.Context, params benchflix.
 
 	return movies, nil
 }
+
+func (r Repository) QueryDetails(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, m.runtime
+			, m.budget
+			, m.tagline
+			, m.metadata
+		FROM movies m
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3 = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`, params.Search, params.YearAdded, params.MinRating, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var movies = make([]benchflix.Movie, 0, params.Limit)
+
+	for rows.Next() {
+		var (
+			movie    benchflix.Movie
+			metadata []byte
+		)
+
+		if err := rows.Scan(&movie.ID, &movie.Title, &movie.AddedAt, &movie.Rating, &movie.Runtime, &movie.Budget, &movie.Tagline, &metadata); err != nil {
+			return nil, err
+		}
+
+		if metadata != nil {
+			movie.Metadata = &benchflix.Metadata{}
+
+			if err := json.Unmarshal(metadata, movie.Metadata); err != nil {
+				return nil, err
+			}
+		}
+
+		movies = append(movies, movie)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QueryDetailsNull(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, m.runtime
+			, m.budget
+			, m.tagline
+			, m.metadata
+		FROM movies m
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3 = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`, params.Search, params.YearAdded, params.MinRating, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var movies = make([]benchflix.Movie, 0, params.Limit)
+
+	for rows.Next() {
+		var (
+			movie    benchflix.Movie
+			runtime  sql.NullInt64
+			budget   sql.NullInt64
+			tagline  sql.NullString
+			metadata []byte
+		)
+
+		if err := rows.Scan(&movie.ID, &movie.Title, &movie.AddedAt, &movie.Rating, &runtime, &budget, &tagline, &metadata); err != nil {
+			return nil, err
+		}
+
+		if runtime.Valid {
+			movie.Runtime = &runtime.Int64
+		}
+
+		if budget.Valid {
+			movie.Budget = &budget.Int64
+		}
+
+		if tagline.Valid {
+			movie.Tagline = &tagline.String
+		}
+
+		if metadata != nil {
+			movie.Metadata = &benchflix.Metadata{}
+
+			if err := json.Unmarshal(metadata, movie.Metadata); err != nil {
+				return nil, err
+			}
+		}
+
+		movies = append(movies, movie)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QuerySearch(ctx context.Context, params benchflix.SearchParams) ([]benchflix.SearchResult, error) {
+	tsquery := "plainto_tsquery"
+
+	if params.Websearch {
+		tsquery = "websearch_to_tsquery"
+	}
+
+	rows, err := r.DB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, d.directors
+			, ts_rank(
+				setweight(to_tsvector('simple', m.title), 'A')
+				|| setweight(to_tsvector('simple', COALESCE(d.names, '')), 'B'),
+				q.query
+			) AS rank
+			, ts_headline('simple', m.title || ' / ' || COALESCE(d.names, ''), q.query) AS headline
+		FROM movies m
+		CROSS JOIN %s('simple', $1) AS q (query)
+		LEFT JOIN LATERAL (
+			SELECT
+				ARRAY_AGG(p.name ORDER BY p.name) AS directors
+				, STRING_AGG(p.name, ' ' ORDER BY p.name) AS names
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+		) d ON true
+		WHERE
+			to_tsvector('simple', m.title) @@ q.query
+			OR EXISTS (
+				SELECT 1
+				FROM movie_directors md
+				JOIN people p ON p.id = md.person_id
+				WHERE md.movie_id = m.id
+				AND to_tsvector('simple', p.name) @@ q.query
+			)
+		ORDER BY rank DESC, m.id
+		LIMIT CASE WHEN $2 BETWEEN 1 AND 1000 THEN $2 ELSE 1000 END;
+	`, tsquery), params.Search, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var results = make([]benchflix.SearchResult, 0, params.Limit)
+
+	for rows.Next() {
+		var (
+			result    benchflix.SearchResult
+			directors pq.StringArray
+		)
+
+		if err := rows.Scan(&result.ID, &result.Title, &result.AddedAt, &result.Rating, &directors, &result.Rank, &result.Headline); err != nil {
+			return nil, err
+		}
+
+		result.Directors = directors
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r Repository) QueryFacets(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	var (
+		result     benchflix.FacetResult
+		sb         = &strings.Builder{}
+		args       = make([]any, 0, 3)
+		paramIndex = 1
+		err        error
+	)
+
+	result.Movies, err = r.QueryDashboardPreload(ctx, params)
+	if err != nil {
+		return result, err
+	}
+
+	sb.WriteString(`
+		SELECT
+			CASE WHEN GROUPING(EXTRACT(YEAR FROM m.added_at)) = 0 THEN 'year' ELSE 'rating' END AS facet
+			, CAST(COALESCE(EXTRACT(YEAR FROM m.added_at), FLOOR(m.rating)) AS INT8) AS value
+			, COUNT(*) AS count
+		FROM movies m
+		WHERE 1=1`)
+
+	if params.Search != "" {
+		fmt.Fprintf(sb, ` AND (
+			to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $%d)
+			OR EXISTS (
+			SELECT 1 FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+				AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $%d)
+			)
+		)`, paramIndex, paramIndex)
+		args = append(args, params.Search)
+		paramIndex++
+	}
+
+	if params.YearAdded != 0 {
+		fmt.Fprintf(sb, " AND EXTRACT(YEAR FROM m.added_at) = $%d", paramIndex)
+		args = append(args, params.YearAdded)
+		paramIndex++
+	}
+
+	if params.MinRating != 0 {
+		fmt.Fprintf(sb, " AND m.rating >= $%d", paramIndex)
+		args = append(args, params.MinRating)
+		paramIndex++
+	}
+
+	sb.WriteString(" GROUP BY GROUPING SETS ((EXTRACT(YEAR FROM m.added_at)), (FLOOR(m.rating))) ORDER BY facet, value")
+
+	rows, err := r.DB.QueryContext(ctx, sb.String(), args...)
+	if err != nil {
+		return result, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			facet string
+			f     benchflix.Facet
+		)
+
+		if err := rows.Scan(&facet, &f.Value, &f.Count); err != nil {
+			return result, err
+		}
+
+		if facet == "year" {
+			result.Years = append(result.Years, f)
+		} else {
+			result.Ratings = append(result.Ratings, f)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (r Repository) QueryFacetsBatch(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	return benchflix.FacetResult{}, benchflix.ErrSkip
+}
+
+func (r Repository) QueryTopRated(ctx context.Context, params benchflix.TopParams) ([]benchflix.TopMovie, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		WITH director_ratings AS (
+			SELECT
+				md.person_id
+				, CAST(AVG(m.rating) AS FLOAT8) AS avg_rating
+			FROM movie_directors md
+			JOIN movies m ON m.id = md.movie_id
+			GROUP BY md.person_id
+		), ranked AS (
+			SELECT
+				m.id
+				, m.title
+				, m.added_at
+				, m.rating
+				, CAST(EXTRACT(YEAR FROM m.added_at) AS INT8) AS year
+				, ROW_NUMBER() OVER (PARTITION BY EXTRACT(YEAR FROM m.added_at) ORDER BY m.rating DESC, m.id) AS position
+			FROM movies m
+			WHERE $1::NUMERIC = 0 OR m.rating >= $1
+		)
+		SELECT
+			r.id
+			, r.title
+			, r.added_at
+			, r.rating
+			, r.year
+			, r.position
+			, d.directors
+			, d.ratings
+		FROM ranked r
+		LEFT JOIN LATERAL (
+			SELECT
+				ARRAY_AGG(p.name ORDER BY p.name) AS directors
+				, ARRAY_AGG(dr.avg_rating ORDER BY p.name) AS ratings
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			JOIN director_ratings dr ON dr.person_id = md.person_id
+			WHERE md.movie_id = r.id
+		) d ON true
+		WHERE r.position <= CASE WHEN $2 BETWEEN 1 AND 10 THEN $2 ELSE 10 END
+		ORDER BY r.year, r.position;
+	`, params.MinRating, params.PerYear)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var movies []benchflix.TopMovie
+
+	for rows.Next() {
+		var (
+			movie     benchflix.TopMovie
+			directors pq.StringArray
+			ratings   pq.Float64Array
+		)
+
+		if err := rows.Scan(&movie.ID, &movie.Title, &movie.AddedAt, &movie.Rating, &movie.Year, &movie.Position, &directors, &ratings); err != nil {
+			return nil, err
+		}
+
+		movie.Directors = directors
+		movie.DirectorRatings = ratings
+
+		movies = append(movies, movie)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QueryCollaborators(ctx context.Context, params benchflix.GraphParams) ([]benchflix.Collaborator, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		WITH RECURSIVE graph (person_id, depth, path, movies) AS (
+			SELECT
+				CAST(p.id AS INT8)
+				, CAST(0 AS INT8)
+				, ARRAY[CAST(p.id AS INT8)]
+				, CAST(ARRAY[] AS TEXT[])
+			FROM people p
+			WHERE p.name = $1
+			UNION ALL
+			SELECT
+				CAST(b.person_id AS INT8)
+				, g.depth + 1
+				, g.path || CAST(b.person_id AS INT8)
+				, g.movies || m.title
+			FROM graph g
+			JOIN movie_directors a ON a.person_id = g.person_id
+			JOIN movie_directors b ON b.movie_id = a.movie_id
+			JOIN movies m ON m.id = a.movie_id
+			WHERE g.depth < CASE WHEN $2 BETWEEN 1 AND 3 THEN $2 ELSE 3 END
+			AND b.person_id <> ALL (g.path)
+		)
+		SELECT
+			c.person_id
+			, p.name
+			, c.depth
+			, c.movies
+		FROM (
+			SELECT DISTINCT ON (g.person_id)
+				g.person_id
+				, g.depth
+				, g.movies
+			FROM graph g
+			WHERE g.depth > 0
+			ORDER BY g.person_id, g.depth, g.movies
+		) c
+		JOIN people p ON p.id = c.person_id
+		ORDER BY c.depth, p.name;
+	`, params.Director, params.Depth)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var collaborators []benchflix.Collaborator
+
+	for rows.Next() {
+		var (
+			collaborator benchflix.Collaborator
+			movies       pq.StringArray
+		)
+
+		if err := rows.Scan(&collaborator.ID, &collaborator.Name, &collaborator.Depth, &movies); err != nil {
+			return nil, err
+		}
+
+		collaborator.Movies = movies
+
+		collaborators = append(collaborators, collaborator)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return collaborators, nil
+}
+
+func (r Repository) QueryMovie(ctx context.Context, id int64) (benchflix.Movie, error) {
+	var (
+		movie     benchflix.Movie
+		directors pq.StringArray
+	)
+
+	err := r.DB.QueryRowContext(ctx, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, d.directors
+		FROM movies m
+		LEFT JOIN LATERAL (
+			SELECT ARRAY_AGG(p.name ORDER BY p.name) AS directors
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+		) d ON true
+		WHERE m.id = $1;
+	`, id).Scan(&movie.ID, &movie.Title, &movie.AddedAt, &movie.Rating, &directors)
+	if errors.Is(err, sql.ErrNoRows) {
+		return movie, benchflix.ErrNotFound
+	}
+
+	if err != nil {
+		return movie, err
+	}
+
+	movie.Directors = directors
+
+	return movie, nil
+}
+
+func (r Repository) QueryWide(ctx context.Context, params benchflix.WideParams) ([]benchflix.WideRow, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			id
+			, int01
+			, int02
+			, int03
+			, int04
+			, int05
+			, int06
+			, int07
+			, int08
+			, int09
+			, int10
+			, int11
+			, int12
+			, num01
+			, num02
+			, num03
+			, num04
+			, num05
+			, num06
+			, num07
+			, num08
+			, num09
+			, num10
+			, num11
+			, num12
+			, time01
+			, time02
+			, time03
+			, time04
+			, time05
+			, time06
+			, time07
+			, time08
+			, time09
+			, time10
+			, time11
+			, time12
+			, tags01
+			, tags02
+			, tags03
+			, tags04
+			, tags05
+			, tags06
+			, tags07
+			, tags08
+			, tags09
+			, tags10
+			, tags11
+			, tags12
+			, note01
+			, note02
+			, note03
+			, note04
+			, note05
+			, note06
+			, note07
+			, note08
+			, note09
+			, note10
+			, note11
+			, note12
+		FROM wide_rows
+		WHERE id > $1
+		ORDER BY id
+		LIMIT CASE WHEN $2 BETWEEN 1 AND 1000 THEN $2 ELSE 1000 END;
+	`, params.After, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var wide = make([]benchflix.WideRow, 0, params.Limit)
+
+	for rows.Next() {
+		var w benchflix.WideRow
+
+		if err := rows.Scan(
+			&w.ID,
+			&w.Int01,
+			&w.Int02,
+			&w.Int03,
+			&w.Int04,
+			&w.Int05,
+			&w.Int06,
+			&w.Int07,
+			&w.Int08,
+			&w.Int09,
+			&w.Int10,
+			&w.Int11,
+			&w.Int12,
+			&w.Num01,
+			&w.Num02,
+			&w.Num03,
+			&w.Num04,
+			&w.Num05,
+			&w.Num06,
+			&w.Num07,
+			&w.Num08,
+			&w.Num09,
+			&w.Num10,
+			&w.Num11,
+			&w.Num12,
+			&w.Time01,
+			&w.Time02,
+			&w.Time03,
+			&w.Time04,
+			&w.Time05,
+			&w.Time06,
+			&w.Time07,
+			&w.Time08,
+			&w.Time09,
+			&w.Time10,
+			&w.Time11,
+			&w.Time12,
+			(*pq.StringArray)(&w.Tags01),
+			(*pq.StringArray)(&w.Tags02),
+			(*pq.StringArray)(&w.Tags03),
+			(*pq.StringArray)(&w.Tags04),
+			(*pq.StringArray)(&w.Tags05),
+			(*pq.StringArray)(&w.Tags06),
+			(*pq.StringArray)(&w.Tags07),
+			(*pq.StringArray)(&w.Tags08),
+			(*pq.StringArray)(&w.Tags09),
+			(*pq.StringArray)(&w.Tags10),
+			(*pq.StringArray)(&w.Tags11),
+			(*pq.StringArray)(&w.Tags12),
+			&w.Note01,
+			&w.Note02,
+			&w.Note03,
+			&w.Note04,
+			&w.Note05,
+			&w.Note06,
+			&w.Note07,
+			&w.Note08,
+			&w.Note09,
+			&w.Note10,
+			&w.Note11,
+			&w.Note12,
+		); err != nil {
+			return nil, err
+		}
+
+		wide = append(wide, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return wide, nil
+}
+
+func (r Repository) QueryDirectors(ctx context.Context, params benchflix.PreloadParams) ([]benchflix.MovieDirectors, error) {
+	var query string
+
+	switch params.Strategy {
+	case benchflix.PreloadAny:
+		query = `
+			SELECT md.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+			FROM movie_directors md
+			JOIN people ON people.id = md.person_id
+			WHERE md.movie_id = ANY ($1)
+			GROUP BY md.movie_id;
+		`
+	case benchflix.PreloadUnnest:
+		query = `
+			SELECT i.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+			FROM unnest($1::INT8[]) WITH ORDINALITY AS i (movie_id, ord)
+			JOIN movie_directors md ON md.movie_id = i.movie_id
+			JOIN people ON people.id = md.person_id
+			GROUP BY i.ord, i.movie_id
+			ORDER BY i.ord;
+		`
+	case benchflix.PreloadTempTable, benchflix.PreloadBatch:
+		return nil, benchflix.ErrSkip
+	default:
+		return nil, fmt.Errorf("invalid strategy: %s", params.Strategy)
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, params.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var movieDirectors []benchflix.MovieDirectors
+
+	for rows.Next() {
+		var (
+			md        benchflix.MovieDirectors
+			directors pq.StringArray
+		)
+
+		if err := rows.Scan(&md.MovieID, &directors); err != nil {
+			return nil, err
+		}
+
+		md.Directors = directors
+
+		movieDirectors = append(movieDirectors, md)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movieDirectors, nil
+}
+
+func (r Repository) WithLoader(wait time.Duration, maxBatch int) benchflix.Repository {
+	r.Loader = benchflix.NewDirectorsLoader(wait, maxBatch, r.QueryDirectors)
+
+	return r
+}
+
+func (r Repository) PoolStats() benchflix.PoolStats {
+	return benchflix.SQLPoolStats(r.DB)
+}
+
+func (r Repository) Close() error {
+	return r.DB.Close()
+}
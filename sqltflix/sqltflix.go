@@ -2,26 +2,64 @@ package sqltflix
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/go-sqlt/benchflix"
 	"github.com/go-sqlt/sqlt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type MovieDirectors struct {
-	MovieID   int64
-	Directors []string
+type FacetCount struct {
+	Facet string
+	Value int64
+	Count int64
 }
 
-func NewRepository(conn string, min, max int, idle time.Duration, config sqlt.Config) Repository {
-	cfg := benchflix.Must(pgxpool.ParseConfig(conn))
+func init() {
+	benchflix.RegisterAdapter("sqlt", func(conn string, min, max int, idle time.Duration, opts ...benchflix.Option) (benchflix.Repository, error) {
+		config := sqlt.Config{}
+
+		if size := benchflix.NewOptions(opts...).ExpressionSize; size > 0 {
+			config = sqlt.ExpressionSize(size)
+		}
+
+		repo, err := Open(conn, min, max, idle, config, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return repo, nil
+	})
+}
+
+func NewRepository(conn string, min, max int, idle time.Duration, config sqlt.Config, opts ...benchflix.Option) Repository {
+	return benchflix.Must(Open(conn, min, max, idle, config, opts...))
+}
+
+// Open is NewRepository returning errors.
+func Open(conn string, min, max int, idle time.Duration, config sqlt.Config, opts ...benchflix.Option) (Repository, error) {
+	cfg, err := pgxpool.ParseConfig(conn)
+	if err != nil {
+		return Repository{}, err
+	}
 
 	cfg.MaxConns = int32(max)
 	cfg.MinConns = int32(min)
 	cfg.MaxConnIdleTime = idle
 
-	pool := benchflix.Must(pgxpool.NewWithConfig(context.Background(), cfg))
+	if benchflix.NewOptions(opts...).Instrument {
+		benchflix.InstrumentPgx(cfg.ConnConfig)
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		return Repository{}, err
+	}
 
 	return Repository{
 		Pool: pool,
@@ -86,7 +124,7 @@ func NewRepository(conn string, min, max int, idle time.Duration, config sqlt.Co
 				LIMIT CASE WHEN {{ .Limit }} BETWEEN 1 AND 1000 THEN {{ .Limit }} ELSE 1000 END;
 			`),
 		),
-		QueryDirectorsStatement: sqlt.AllPgx[[]int64, MovieDirectors](
+		QueryDirectorsStatement: sqlt.AllPgx[[]int64, benchflix.MovieDirectors](
 			config,
 			sqlt.Parse(`
 				SELECT
@@ -176,16 +214,492 @@ func NewRepository(conn string, min, max int, idle time.Duration, config sqlt.Co
 				{{ if and (gt .Limit 0) (lt .Limit 1000) }} LIMIT {{ .Limit }}{{ else }} LIMIT 1000{{ end }}
 			`),
 		),
-	}
+		QueryDetailsStatement: sqlt.AllPgx[benchflix.ListParams, benchflix.Movie](
+			config,
+			sqlt.Parse(`
+				SELECT
+					m.id                    {{ Scan.Int.To "ID" }}
+					, m.title               {{ Scan.String.To "Title" }}
+					, m.added_at            {{ Scan.Time.To "AddedAt" }}
+					, m.rating              {{ Scan.Float.To "Rating" }}
+					, m.runtime             {{ Scan.Nullable.Int.To "Runtime" }}
+					, m.budget              {{ Scan.Nullable.Int.To "Budget" }}
+					, m.tagline             {{ Scan.Nullable.String.To "Tagline" }}
+					, m.metadata            {{ Scan.Nullable.JSON.To "Metadata" }}
+				FROM movies m
+				WHERE
+					(
+						{{ .Search }} = ''
+						OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', {{ .Search }})
+						OR EXISTS (
+							SELECT 1
+							FROM movie_directors md
+							JOIN people p ON p.id = md.person_id
+							WHERE md.movie_id = m.id
+							AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', {{ .Search }})
+						)
+					)
+					AND ({{ .YearAdded }} = 0 OR EXTRACT(YEAR FROM m.added_at) = {{ .YearAdded }})
+					AND ({{ .MinRating }} = 0 OR m.rating >= {{ .MinRating }})
+				ORDER BY m.rating DESC
+				LIMIT CASE WHEN {{ .Limit }} BETWEEN 1 AND 1000 THEN {{ .Limit }} ELSE 1000 END;
+			`),
+		),
+		QueryDetailsNullStatement: sqlt.AllPgx[benchflix.ListParams, MovieDetailsNull](
+			config,
+			sqlt.Parse(`
+				SELECT
+					m.id                    {{ Scan.Int.To "ID" }}
+					, m.title               {{ Scan.String.To "Title" }}
+					, m.added_at            {{ Scan.Time.To "AddedAt" }}
+					, m.rating              {{ Scan.Float.To "Rating" }}
+					, m.runtime             {{ Scan.To "Runtime" }}
+					, m.budget              {{ Scan.To "Budget" }}
+					, m.tagline             {{ Scan.To "Tagline" }}
+					, m.metadata            {{ Scan.Nullable.JSON.To "Metadata" }}
+				FROM movies m
+				WHERE
+					(
+						{{ .Search }} = ''
+						OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', {{ .Search }})
+						OR EXISTS (
+							SELECT 1
+							FROM movie_directors md
+							JOIN people p ON p.id = md.person_id
+							WHERE md.movie_id = m.id
+							AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', {{ .Search }})
+						)
+					)
+					AND ({{ .YearAdded }} = 0 OR EXTRACT(YEAR FROM m.added_at) = {{ .YearAdded }})
+					AND ({{ .MinRating }} = 0 OR m.rating >= {{ .MinRating }})
+				ORDER BY m.rating DESC
+				LIMIT CASE WHEN {{ .Limit }} BETWEEN 1 AND 1000 THEN {{ .Limit }} ELSE 1000 END;
+			`),
+		),
+		QuerySearchStatement: sqlt.AllPgx[benchflix.SearchParams, benchflix.SearchResult](
+			config,
+			sqlt.Parse(`
+				SELECT
+					m.id                    {{ Scan.Int.To "ID" }}
+					, m.title               {{ Scan.String.To "Title" }}
+					, m.added_at            {{ Scan.Time.To "AddedAt" }}
+					, m.rating              {{ Scan.Float.To "Rating" }}
+					, d.directors           {{ Scan.StringSlice.To "Directors" }}
+					, ts_rank(
+						setweight(to_tsvector('simple', m.title), 'A')
+						|| setweight(to_tsvector('simple', COALESCE(d.names, '')), 'B'),
+						q.query
+					) AS rank               {{ Scan.Float.To "Rank" }}
+					, ts_headline('simple', m.title || ' / ' || COALESCE(d.names, ''), q.query)
+						AS headline         {{ Scan.String.To "Headline" }}
+				FROM movies m
+				CROSS JOIN
+					{{ if .Websearch }} websearch_to_tsquery{{ else }} plainto_tsquery{{ end }}('simple', {{ .Search }})
+					AS q (query)
+				LEFT JOIN LATERAL (
+					SELECT
+						ARRAY_AGG(p.name ORDER BY p.name) AS directors
+						, STRING_AGG(p.name, ' ' ORDER BY p.name) AS names
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+				) d ON true
+				WHERE
+					to_tsvector('simple', m.title) @@ q.query
+					OR EXISTS (
+						SELECT 1
+						FROM movie_directors md
+						JOIN people p ON p.id = md.person_id
+						WHERE md.movie_id = m.id
+						AND to_tsvector('simple', p.name) @@ q.query
+					)
+				ORDER BY rank DESC, m.id
+				{{ if and (gt .Limit 0) (lt .Limit 1000) }} LIMIT {{ .Limit }}{{ else }} LIMIT 1000{{ end }}
+			`),
+		),
+		QueryFacetsStatement: sqlt.AllPgx[benchflix.DashboardParams, FacetCount](
+			config,
+			sqlt.Parse(`
+				SELECT
+					CASE WHEN GROUPING(EXTRACT(YEAR FROM m.added_at)) = 0 THEN 'year' ELSE 'rating' END
+						AS facet		{{ Scan.String.To "Facet" }}
+					, CAST(COALESCE(EXTRACT(YEAR FROM m.added_at), FLOOR(m.rating)) AS INT8)
+						AS value		{{ Scan.Int.To "Value" }}
+					, COUNT(*) AS count	{{ Scan.Int.To "Count" }}
+				FROM movies m
+				WHERE 1=1
+				{{ if .Search }}
+					AND (
+						to_tsvector('simple', m.title) @@ plainto_tsquery('simple', {{ .Search }})
+						OR EXISTS (
+							SELECT 1
+							FROM movie_directors md
+							JOIN people p ON p.id = md.person_id
+							WHERE md.movie_id = m.id
+							AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', {{ .Search }})
+						)
+					)
+				{{ end }}
+				{{ if .YearAdded }} AND EXTRACT(YEAR FROM m.added_at) = {{ .YearAdded }}{{ end }}
+				{{ if .MinRating }} AND m.rating >= {{ .MinRating }}{{ end }}
+				GROUP BY GROUPING SETS ((EXTRACT(YEAR FROM m.added_at)), (FLOOR(m.rating)))
+				ORDER BY facet, value
+			`),
+		),
+		QueryDashboardPreloadExpression: sqlt.CustomPgx[benchflix.DashboardParams, benchflix.Movie](
+			expression[benchflix.Movie],
+			config,
+			sqlt.Parse(`
+				SELECT
+					m.id                    {{ Scan.Int.To "ID" }}
+					, m.title               {{ Scan.String.To "Title" }}
+					, m.added_at            {{ Scan.Time.To "AddedAt" }}
+					, m.rating              {{ Scan.Float.To "Rating" }}
+				FROM movies m
+				WHERE 1=1
+				{{ if .Search }}
+					AND (
+						to_tsvector('simple', m.title) @@ plainto_tsquery('simple', {{ .Search }})
+						OR EXISTS (
+							SELECT 1
+							FROM movie_directors md
+							JOIN people p ON p.id = md.person_id
+							WHERE md.movie_id = m.id
+							AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', {{ .Search }})
+						)
+					)
+				{{ end }}
+				{{ if .YearAdded }} AND EXTRACT(YEAR FROM m.added_at) = {{ .YearAdded }}{{ end }}
+				{{ if .MinRating }} AND m.rating >= {{ .MinRating }}{{ end }}
+				ORDER BY
+				{{ if eq .Sort "title" }} m.title
+					{{ else if eq .Sort "added_at" }} m.added_at
+					{{ else }} m.rating
+				{{ end }}  	 
+				{{ if .Desc }} DESC{{ else }} ASC {{ end }}
+				{{ if and (gt .Limit 0) (lt .Limit 1000) }} LIMIT {{ .Limit }}{{ else }} LIMIT 1000{{ end }}
+			`),
+		),
+		QueryFacetsExpression: sqlt.CustomPgx[benchflix.DashboardParams, FacetCount](
+			expression[FacetCount],
+			config,
+			sqlt.Parse(`
+				SELECT
+					CASE WHEN GROUPING(EXTRACT(YEAR FROM m.added_at)) = 0 THEN 'year' ELSE 'rating' END
+						AS facet		{{ Scan.String.To "Facet" }}
+					, CAST(COALESCE(EXTRACT(YEAR FROM m.added_at), FLOOR(m.rating)) AS INT8)
+						AS value		{{ Scan.Int.To "Value" }}
+					, COUNT(*) AS count	{{ Scan.Int.To "Count" }}
+				FROM movies m
+				WHERE 1=1
+				{{ if .Search }}
+					AND (
+						to_tsvector('simple', m.title) @@ plainto_tsquery('simple', {{ .Search }})
+						OR EXISTS (
+							SELECT 1
+							FROM movie_directors md
+							JOIN people p ON p.id = md.person_id
+							WHERE md.movie_id = m.id
+							AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', {{ .Search }})
+						)
+					)
+				{{ end }}
+				{{ if .YearAdded }} AND EXTRACT(YEAR FROM m.added_at) = {{ .YearAdded }}{{ end }}
+				{{ if .MinRating }} AND m.rating >= {{ .MinRating }}{{ end }}
+				GROUP BY GROUPING SETS ((EXTRACT(YEAR FROM m.added_at)), (FLOOR(m.rating)))
+				ORDER BY facet, value
+			`),
+		),
+		QueryTopRatedStatement: sqlt.AllPgx[benchflix.TopParams, benchflix.TopMovie](
+			config,
+			sqlt.Parse(`
+				WITH director_ratings AS (
+					SELECT
+						md.person_id
+						, CAST(AVG(m.rating) AS FLOAT8) AS avg_rating
+					FROM movie_directors md
+					JOIN movies m ON m.id = md.movie_id
+					GROUP BY md.person_id
+				), ranked AS (
+					SELECT
+						m.id
+						, m.title
+						, m.added_at
+						, m.rating
+						, CAST(EXTRACT(YEAR FROM m.added_at) AS INT8) AS year
+						, ROW_NUMBER() OVER (PARTITION BY EXTRACT(YEAR FROM m.added_at) ORDER BY m.rating DESC, m.id) AS position
+					FROM movies m
+					{{ if .MinRating }} WHERE m.rating >= {{ .MinRating }}{{ end }}
+				)
+				SELECT
+					r.id                    {{ Scan.Int.To "ID" }}
+					, r.title               {{ Scan.String.To "Title" }}
+					, r.added_at            {{ Scan.Time.To "AddedAt" }}
+					, r.rating              {{ Scan.Float.To "Rating" }}
+					, r.year                {{ Scan.Int.To "Year" }}
+					, r.position            {{ Scan.Int.To "Position" }}
+					, d.directors           {{ Scan.StringSlice.To "Directors" }}
+					, d.ratings             {{ Scan.Nullable.JSON.To "DirectorRatings" }}
+				FROM ranked r
+				LEFT JOIN LATERAL (
+					SELECT
+						ARRAY_AGG(p.name ORDER BY p.name) AS directors
+						, JSON_AGG(dr.avg_rating ORDER BY p.name) AS ratings
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					JOIN director_ratings dr ON dr.person_id = md.person_id
+					WHERE md.movie_id = r.id
+				) d ON true
+				WHERE r.position <= {{ if and (gt .PerYear 0) (le .PerYear 10) }}{{ .PerYear }}{{ else }}10{{ end }}
+				ORDER BY r.year, r.position
+			`),
+		),
+		QueryCollaboratorsStatement: sqlt.AllPgx[benchflix.GraphParams, benchflix.Collaborator](
+			config,
+			sqlt.Parse(`
+				WITH RECURSIVE graph (person_id, depth, path, movies) AS (
+					SELECT
+						CAST(p.id AS INT8)
+						, CAST(0 AS INT8)
+						, ARRAY[CAST(p.id AS INT8)]
+						, CAST(ARRAY[] AS TEXT[])
+					FROM people p
+					WHERE p.name = {{ .Director }}
+					UNION ALL
+					SELECT
+						CAST(b.person_id AS INT8)
+						, g.depth + 1
+						, g.path || CAST(b.person_id AS INT8)
+						, g.movies || m.title
+					FROM graph g
+					JOIN movie_directors a ON a.person_id = g.person_id
+					JOIN movie_directors b ON b.movie_id = a.movie_id
+					JOIN movies m ON m.id = a.movie_id
+					WHERE g.depth < {{ if and (gt .Depth 0) (le .Depth 3) }}{{ .Depth }}{{ else }}3{{ end }}
+					AND b.person_id <> ALL (g.path)
+				)
+				SELECT
+					c.person_id             {{ Scan.Int.To "ID" }}
+					, p.name                {{ Scan.String.To "Name" }}
+					, c.depth               {{ Scan.Int.To "Depth" }}
+					, c.movies              {{ Scan.StringSlice.To "Movies" }}
+				FROM (
+					SELECT DISTINCT ON (g.person_id)
+						g.person_id
+						, g.depth
+						, g.movies
+					FROM graph g
+					WHERE g.depth > 0
+					ORDER BY g.person_id, g.depth, g.movies
+				) c
+				JOIN people p ON p.id = c.person_id
+				ORDER BY c.depth, p.name
+			`),
+		),
+		QueryMovieStatement: sqlt.FirstPgx[int64, benchflix.Movie](
+			config,
+			sqlt.Parse(`
+				SELECT
+					m.id                    {{ Scan.Int.To "ID" }}
+					, m.title               {{ Scan.String.To "Title" }}
+					, m.added_at            {{ Scan.Time.To "AddedAt" }}
+					, m.rating              {{ Scan.Float.To "Rating" }}
+					, d.directors           {{ Scan.StringSlice.To "Directors" }}
+				FROM movies m
+				LEFT JOIN LATERAL (
+					SELECT ARRAY_AGG(p.name ORDER BY p.name) AS directors
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+				) d ON true
+				WHERE m.id = {{ . }}
+			`),
+		),
+		QueryWideStatement: sqlt.AllPgx[benchflix.WideParams, benchflix.WideRow](
+			config,
+			sqlt.Parse(`
+				SELECT
+					id                     {{ Scan.Int.To "ID" }}
+					, int01                {{ Scan.Int.To "Int01" }}
+					, int02                {{ Scan.Int.To "Int02" }}
+					, int03                {{ Scan.Int.To "Int03" }}
+					, int04                {{ Scan.Int.To "Int04" }}
+					, int05                {{ Scan.Int.To "Int05" }}
+					, int06                {{ Scan.Int.To "Int06" }}
+					, int07                {{ Scan.Int.To "Int07" }}
+					, int08                {{ Scan.Int.To "Int08" }}
+					, int09                {{ Scan.Int.To "Int09" }}
+					, int10                {{ Scan.Int.To "Int10" }}
+					, int11                {{ Scan.Int.To "Int11" }}
+					, int12                {{ Scan.Int.To "Int12" }}
+					, num01                {{ Scan.Float.To "Num01" }}
+					, num02                {{ Scan.Float.To "Num02" }}
+					, num03                {{ Scan.Float.To "Num03" }}
+					, num04                {{ Scan.Float.To "Num04" }}
+					, num05                {{ Scan.Float.To "Num05" }}
+					, num06                {{ Scan.Float.To "Num06" }}
+					, num07                {{ Scan.Float.To "Num07" }}
+					, num08                {{ Scan.Float.To "Num08" }}
+					, num09                {{ Scan.Float.To "Num09" }}
+					, num10                {{ Scan.Float.To "Num10" }}
+					, num11                {{ Scan.Float.To "Num11" }}
+					, num12                {{ Scan.Float.To "Num12" }}
+					, time01               {{ Scan.Time.To "Time01" }}
+					, time02               {{ Scan.Time.To "Time02" }}
+					, time03               {{ Scan.Time.To "Time03" }}
+					, time04               {{ Scan.Time.To "Time04" }}
+					, time05               {{ Scan.Time.To "Time05" }}
+					, time06               {{ Scan.Time.To "Time06" }}
+					, time07               {{ Scan.Time.To "Time07" }}
+					, time08               {{ Scan.Time.To "Time08" }}
+					, time09               {{ Scan.Time.To "Time09" }}
+					, time10               {{ Scan.Time.To "Time10" }}
+					, time11               {{ Scan.Time.To "Time11" }}
+					, time12               {{ Scan.Time.To "Time12" }}
+					, tags01               {{ Scan.StringSlice.To "Tags01" }}
+					, tags02               {{ Scan.StringSlice.To "Tags02" }}
+					, tags03               {{ Scan.StringSlice.To "Tags03" }}
+					, tags04               {{ Scan.StringSlice.To "Tags04" }}
+					, tags05               {{ Scan.StringSlice.To "Tags05" }}
+					, tags06               {{ Scan.StringSlice.To "Tags06" }}
+					, tags07               {{ Scan.StringSlice.To "Tags07" }}
+					, tags08               {{ Scan.StringSlice.To "Tags08" }}
+					, tags09               {{ Scan.StringSlice.To "Tags09" }}
+					, tags10               {{ Scan.StringSlice.To "Tags10" }}
+					, tags11               {{ Scan.StringSlice.To "Tags11" }}
+					, tags12               {{ Scan.StringSlice.To "Tags12" }}
+					, note01               {{ Scan.Nullable.String.To "Note01" }}
+					, note02               {{ Scan.Nullable.String.To "Note02" }}
+					, note03               {{ Scan.Nullable.String.To "Note03" }}
+					, note04               {{ Scan.Nullable.String.To "Note04" }}
+					, note05               {{ Scan.Nullable.String.To "Note05" }}
+					, note06               {{ Scan.Nullable.String.To "Note06" }}
+					, note07               {{ Scan.Nullable.String.To "Note07" }}
+					, note08               {{ Scan.Nullable.String.To "Note08" }}
+					, note09               {{ Scan.Nullable.String.To "Note09" }}
+					, note10               {{ Scan.Nullable.String.To "Note10" }}
+					, note11               {{ Scan.Nullable.String.To "Note11" }}
+					, note12               {{ Scan.Nullable.String.To "Note12" }}
+				FROM wide_rows
+				WHERE id > {{ .After }}
+				ORDER BY id
+				LIMIT CASE WHEN {{ .Limit }} BETWEEN 1 AND 1000 THEN {{ .Limit }} ELSE 1000 END
+			`),
+		),
+		QueryListJSONStatement: sqlt.AllPgx[benchflix.ListParams, JSONMovie](
+			config,
+			sqlt.Parse(`
+				SELECT
+					m.id                    {{ Scan.Int.To "Movie.ID" }}
+					, m.title               {{ Scan.String.To "Movie.Title" }}
+					, m.added_at            {{ Scan.Time.To "Movie.AddedAt" }}
+					, m.rating              {{ Scan.Float.To "Movie.Rating" }}
+					, COALESCE(d.directors, '[]') {{ Scan.JSON.To "People" }}
+				FROM movies m
+				LEFT JOIN LATERAL (
+					SELECT json_agg(json_build_object('id', p.id, 'name', p.name) ORDER BY p.name) AS directors
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+				) d ON true
+				WHERE
+					(
+						{{ .Search }} = ''
+						OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', {{ .Search }})
+						OR EXISTS (
+							SELECT 1
+							FROM movie_directors md
+							JOIN people p ON p.id = md.person_id
+							WHERE md.movie_id = m.id
+							AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', {{ .Search }})
+						)
+					)
+					AND ({{ .YearAdded }} = 0 OR EXTRACT(YEAR FROM m.added_at) = {{ .YearAdded }})
+					AND ({{ .MinRating }} = 0 OR m.rating >= {{ .MinRating }})
+				ORDER BY m.rating DESC
+				LIMIT CASE WHEN {{ .Limit }} BETWEEN 1 AND 1000 THEN {{ .Limit }} ELSE 1000 END;
+			`),
+		),
+		QueryMovieDirectorsStatement: sqlt.AllPgx[int64, string](
+			config,
+			sqlt.Parse(`
+				SELECT people.name {{ Scan.String }}
+				FROM movie_directors md
+				JOIN people ON people.id = md.person_id
+				WHERE md.movie_id = {{ . }}
+				ORDER BY people.name;
+			`),
+		),
+		QueryDirectorsUnnestStatement: sqlt.AllPgx[[]int64, benchflix.MovieDirectors](
+			config,
+			sqlt.Parse(`
+				SELECT
+					i.movie_id			{{ Scan.Int.To "MovieID" }}
+					, ARRAY_AGG(people.name ORDER BY people.name)
+						AS directors 	{{ Scan.StringSlice.To "Directors" }}
+				FROM unnest({{ . }}::INT8[]) WITH ORDINALITY AS i (movie_id, ord)
+				JOIN movie_directors md ON md.movie_id = i.movie_id
+				JOIN people ON people.id = md.person_id
+				GROUP BY i.ord, i.movie_id
+				ORDER BY i.ord;
+			`),
+		),
+		QueryDirectorsTempTableStatement: sqlt.AllPgx[any, benchflix.MovieDirectors](
+			config,
+			sqlt.Parse(`
+				SELECT
+					i.movie_id			{{ Scan.Int.To "MovieID" }}
+					, ARRAY_AGG(people.name ORDER BY people.name)
+						AS directors 	{{ Scan.StringSlice.To "Directors" }}
+				FROM preload_ids i
+				JOIN movie_directors md ON md.movie_id = i.movie_id
+				JOIN people ON people.id = md.person_id
+				GROUP BY i.ord, i.movie_id
+				ORDER BY i.ord;
+			`),
+		),
+	}, nil
+}
+
+type MovieDetailsNull struct {
+	ID              int64
+	Title           string
+	AddedAt         time.Time
+	Rating          float64
+	Runtime, Budget pgtype.Int8
+	Tagline         pgtype.Text
+	Metadata        *benchflix.Metadata
+}
+
+type JSONMovie struct {
+	Movie  benchflix.Movie
+	People []benchflix.Person
 }
 
 type Repository struct {
 	Pool                           *pgxpool.Pool
 	QueryListStatement             sqlt.PgxStatement[benchflix.ListParams, []benchflix.Movie]
 	QueryListPreloadStatement      sqlt.PgxStatement[benchflix.ListParams, []benchflix.Movie]
-	QueryDirectorsStatement        sqlt.PgxStatement[[]int64, []MovieDirectors]
+	QueryDirectorsStatement        sqlt.PgxStatement[[]int64, []benchflix.MovieDirectors]
 	QueryDashboardStatement        sqlt.PgxStatement[benchflix.DashboardParams, []benchflix.Movie]
 	QueryDashboardPreloadStatement sqlt.PgxStatement[benchflix.DashboardParams, []benchflix.Movie]
+	QueryDetailsStatement          sqlt.PgxStatement[benchflix.ListParams, []benchflix.Movie]
+	QueryDetailsNullStatement      sqlt.PgxStatement[benchflix.ListParams, []MovieDetailsNull]
+	QuerySearchStatement           sqlt.PgxStatement[benchflix.SearchParams, []benchflix.SearchResult]
+	QueryFacetsStatement           sqlt.PgxStatement[benchflix.DashboardParams, []FacetCount]
+	QueryTopRatedStatement         sqlt.PgxStatement[benchflix.TopParams, []benchflix.TopMovie]
+	QueryCollaboratorsStatement    sqlt.PgxStatement[benchflix.GraphParams, []benchflix.Collaborator]
+	QueryMovieStatement            sqlt.PgxStatement[int64, benchflix.Movie]
+	QueryWideStatement             sqlt.PgxStatement[benchflix.WideParams, []benchflix.WideRow]
+
+	QueryMovieDirectorsStatement     sqlt.PgxStatement[int64, []string]
+	QueryListJSONStatement           sqlt.PgxStatement[benchflix.ListParams, []JSONMovie]
+	QueryDashboardPreloadExpression  sqlt.PgxStatement[benchflix.DashboardParams, sqlt.Expression[benchflix.Movie]]
+	QueryFacetsExpression            sqlt.PgxStatement[benchflix.DashboardParams, sqlt.Expression[FacetCount]]
+	QueryDirectorsUnnestStatement    sqlt.PgxStatement[[]int64, []benchflix.MovieDirectors]
+	QueryDirectorsTempTableStatement sqlt.PgxStatement[any, []benchflix.MovieDirectors]
+
+	Loader *benchflix.Loader[int64, []string]
 }
 
 func (r Repository) QueryList(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
@@ -212,6 +726,19 @@ func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListP
 		idMap[m.ID] = i
 	}
 
+	if r.Loader != nil {
+		directors, err := r.Loader.LoadMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, d := range directors {
+			movies[idMap[id]].Directors = d
+		}
+
+		return movies, nil
+	}
+
 	movieDirectors, err := r.QueryDirectorsStatement.Exec(ctx, r.Pool, ids)
 	if err != nil {
 		return nil, err
@@ -224,6 +751,47 @@ func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListP
 	return movies, nil
 }
 
+func (r Repository) QueryListNPlusOne(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	movies, err := r.QueryListPreloadStatement.Exec(ctx, r.Pool, params)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range movies {
+		movies[i].Directors, err = r.QueryMovieDirectorsStatement.Exec(ctx, r.Pool, movies[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QueryListJSON(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.QueryListJSONStatement.Exec(ctx, r.Pool, params)
+	if err != nil {
+		return nil, err
+	}
+
+	movies := make([]benchflix.Movie, len(rows))
+
+	for i, row := range rows {
+		movies[i] = row.Movie
+
+		if len(row.People) == 0 {
+			continue
+		}
+
+		movies[i].Directors = make([]string, len(row.People))
+
+		for j, p := range row.People {
+			movies[i].Directors[j] = p.Name
+		}
+	}
+
+	return movies, nil
+}
+
 func (r Repository) QueryDashboard(ctx context.Context, params benchflix.DashboardParams) ([]benchflix.Movie, error) {
 	return r.QueryDashboardStatement.Exec(ctx, r.Pool, params)
 }
@@ -248,6 +816,19 @@ func (r Repository) QueryDashboardPreload(ctx context.Context, params benchflix.
 		idMap[m.ID] = i
 	}
 
+	if r.Loader != nil {
+		directors, err := r.Loader.LoadMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, d := range directors {
+			movies[idMap[id]].Directors = d
+		}
+
+		return movies, nil
+	}
+
 	movieDirectors, err := r.QueryDirectorsStatement.Exec(ctx, r.Pool, ids)
 	if err != nil {
 		return nil, err
@@ -259,3 +840,220 @@ func (r Repository) QueryDashboardPreload(ctx context.Context, params benchflix.
 
 	return movies, nil
 }
+
+func (r Repository) QueryDetails(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	return r.QueryDetailsStatement.Exec(ctx, r.Pool, params)
+}
+
+func (r Repository) QueryDetailsNull(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	rows, err := r.QueryDetailsNullStatement.Exec(ctx, r.Pool, params)
+	if err != nil {
+		return nil, err
+	}
+
+	movies := make([]benchflix.Movie, len(rows))
+
+	for i, m := range rows {
+		movies[i] = benchflix.Movie{
+			ID:       m.ID,
+			Title:    m.Title,
+			AddedAt:  m.AddedAt,
+			Rating:   m.Rating,
+			Metadata: m.Metadata,
+		}
+
+		if m.Runtime.Valid {
+			movies[i].Runtime = &m.Runtime.Int64
+		}
+
+		if m.Budget.Valid {
+			movies[i].Budget = &m.Budget.Int64
+		}
+
+		if m.Tagline.Valid {
+			movies[i].Tagline = &m.Tagline.String
+		}
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QuerySearch(ctx context.Context, params benchflix.SearchParams) ([]benchflix.SearchResult, error) {
+	return r.QuerySearchStatement.Exec(ctx, r.Pool, params)
+}
+
+func (r Repository) QueryFacets(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	var (
+		result benchflix.FacetResult
+		err    error
+	)
+
+	result.Movies, err = r.QueryDashboardPreload(ctx, params)
+	if err != nil {
+		return result, err
+	}
+
+	facets, err := r.QueryFacetsStatement.Exec(ctx, r.Pool, params)
+	if err != nil {
+		return result, err
+	}
+
+	for _, f := range facets {
+		if f.Facet == "year" {
+			result.Years = append(result.Years, benchflix.Facet{Value: f.Value, Count: f.Count})
+		} else {
+			result.Ratings = append(result.Ratings, benchflix.Facet{Value: f.Value, Count: f.Count})
+		}
+	}
+
+	return result, nil
+}
+
+func (r Repository) QueryFacetsBatch(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	var (
+		result benchflix.FacetResult
+		facets []FacetCount
+		batch  = &pgx.Batch{}
+	)
+
+	page, err := r.QueryDashboardPreloadExpression.Exec(ctx, r.Pool, params)
+	if err != nil {
+		return result, err
+	}
+
+	counts, err := r.QueryFacetsExpression.Exec(ctx, r.Pool, params)
+	if err != nil {
+		return result, err
+	}
+
+	batch.Queue(page.SQL, page.Args...).Query(func(rows pgx.Rows) (err error) {
+		result.Movies, err = page.Schema.All(rows)
+
+		return err
+	})
+
+	batch.Queue(counts.SQL, counts.Args...).Query(func(rows pgx.Rows) (err error) {
+		facets, err = counts.Schema.All(rows)
+
+		return err
+	})
+
+	if err = r.Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return result, err
+	}
+
+	for _, f := range facets {
+		if f.Facet == "year" {
+			result.Years = append(result.Years, benchflix.Facet{Value: f.Value, Count: f.Count})
+		} else {
+			result.Ratings = append(result.Ratings, benchflix.Facet{Value: f.Value, Count: f.Count})
+		}
+	}
+
+	if len(result.Movies) == 0 || !params.WithDirectors {
+		return result, nil
+	}
+
+	var (
+		ids   = make([]int64, len(result.Movies))
+		idMap = make(map[int64]int, len(result.Movies))
+	)
+
+	for i, m := range result.Movies {
+		ids[i] = m.ID
+		idMap[m.ID] = i
+	}
+
+	movieDirectors, err := r.QueryDirectorsStatement.Exec(ctx, r.Pool, ids)
+	if err != nil {
+		return result, err
+	}
+
+	for _, md := range movieDirectors {
+		result.Movies[idMap[md.MovieID]].Directors = md.Directors
+	}
+
+	return result, nil
+}
+
+// expression renders a statement without running it, so that it can be
+// queued into a pgx.Batch.
+func expression[Dest any](_ context.Context, _ sqlt.Pgx, expr sqlt.Expression[Dest]) (sqlt.Expression[Dest], error) {
+	return expr, nil
+}
+
+func (r Repository) QueryTopRated(ctx context.Context, params benchflix.TopParams) ([]benchflix.TopMovie, error) {
+	return r.QueryTopRatedStatement.Exec(ctx, r.Pool, params)
+}
+
+func (r Repository) QueryCollaborators(ctx context.Context, params benchflix.GraphParams) ([]benchflix.Collaborator, error) {
+	return r.QueryCollaboratorsStatement.Exec(ctx, r.Pool, params)
+}
+
+func (r Repository) QueryMovie(ctx context.Context, id int64) (benchflix.Movie, error) {
+	movie, err := r.QueryMovieStatement.Exec(ctx, r.Pool, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return movie, benchflix.ErrNotFound
+	}
+
+	return movie, err
+}
+
+func (r Repository) QueryWide(ctx context.Context, params benchflix.WideParams) ([]benchflix.WideRow, error) {
+	return r.QueryWideStatement.Exec(ctx, r.Pool, params)
+}
+
+func (r Repository) QueryDirectors(ctx context.Context, params benchflix.PreloadParams) ([]benchflix.MovieDirectors, error) {
+	switch params.Strategy {
+	case benchflix.PreloadAny:
+		return r.QueryDirectorsStatement.Exec(ctx, r.Pool, params.IDs)
+	case benchflix.PreloadUnnest:
+		return r.QueryDirectorsUnnestStatement.Exec(ctx, r.Pool, params.IDs)
+	case benchflix.PreloadTempTable:
+		tx, err := r.Pool.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() { _ = tx.Rollback(ctx) }()
+
+		if _, err = tx.Exec(ctx, `CREATE TEMP TABLE preload_ids (ord INT8, movie_id INT8) ON COMMIT DROP;`); err != nil {
+			return nil, err
+		}
+
+		if _, err = tx.CopyFrom(ctx, pgx.Identifier{"preload_ids"}, []string{"ord", "movie_id"},
+			pgx.CopyFromSlice(len(params.IDs), func(i int) ([]any, error) {
+				return []any{int64(i + 1), params.IDs[i]}, nil
+			}),
+		); err != nil {
+			return nil, err
+		}
+
+		movieDirectors, err := r.QueryDirectorsTempTableStatement.Exec(ctx, tx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return movieDirectors, tx.Commit(ctx)
+	case benchflix.PreloadBatch:
+		return nil, benchflix.ErrSkip
+	default:
+		return nil, fmt.Errorf("invalid strategy: %s", params.Strategy)
+	}
+}
+
+func (r Repository) WithLoader(wait time.Duration, maxBatch int) benchflix.Repository {
+	r.Loader = benchflix.NewDirectorsLoader(wait, maxBatch, r.QueryDirectors)
+
+	return r
+}
+
+func (r Repository) PoolStats() benchflix.PoolStats {
+	return benchflix.PgxPoolStats(r.Pool)
+}
+
+func (r Repository) Close() error {
+	r.Pool.Close()
+
+	return nil
+}
@@ -2,17 +2,41 @@ package sqlxflix
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/go-sqlt/benchflix"
 	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
 	"github.com/lib/pq"
 )
 
-func NewRepository(conn string, min, max int, idle time.Duration) benchflix.Repository {
-	db := benchflix.Must(sqlx.Connect("postgres", conn))
+func init() {
+	benchflix.RegisterAdapter("sqlx", Open)
+}
+
+func NewRepository(conn string, min, max int, idle time.Duration, opts ...benchflix.Option) benchflix.Repository {
+	return benchflix.Must(Open(conn, min, max, idle, opts...))
+}
+
+// Open is NewRepository returning errors.
+func Open(conn string, min, max int, idle time.Duration, opts ...benchflix.Option) (benchflix.Repository, error) {
+	connector, err := benchflix.NewOptions(opts...).Connector(&pq.Driver{}, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	db := sqlx.NewDb(sql.OpenDB(connector), "postgres")
+
+	// like sqlx.Connect
+	if err = db.Ping(); err != nil {
+		_ = db.Close()
+
+		return nil, err
+	}
 
 	db.SetMaxOpenConns(max)
 	db.SetMaxIdleConns(min)
@@ -20,11 +44,12 @@ func NewRepository(conn string, min, max int, idle time.Duration) benchflix.Repo
 
 	return Repository{
 		DB: db,
-	}
+	}, nil
 }
 
 type Repository struct {
-	DB *sqlx.DB
+	DB     *sqlx.DB
+	Loader *benchflix.Loader[int64, []string]
 }
 
 func (r Repository) QueryList(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
@@ -150,6 +175,19 @@ func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListP
 		return movies, nil
 	}
 
+	if r.Loader != nil {
+		directors, err := r.Loader.LoadMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, d := range directors {
+			movies[idMap[id]].Directors = d
+		}
+
+		return movies, nil
+	}
+
 	dirRows, err := r.DB.QueryContext(ctx, `
 		SELECT md.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
 		FROM movie_directors md
@@ -183,6 +221,130 @@ func (r Repository) QueryListPreload(ctx context.Context, params benchflix.ListP
 	return movies, nil
 }
 
+func (r Repository) QueryListNPlusOne(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	movies := make([]benchflix.Movie, 0, params.Limit)
+
+	if err := r.DB.SelectContext(ctx, &movies, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+		FROM movies m
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3::NUMERIC = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`,
+		params.Search, params.YearAdded, params.MinRating, params.Limit); err != nil {
+		return nil, err
+	}
+
+	for i := range movies {
+		var directors []string
+
+		if err := r.DB.SelectContext(ctx, &directors, `
+			SELECT people.name
+			FROM movie_directors md
+			JOIN people ON people.id = md.person_id
+			WHERE md.movie_id = $1
+			ORDER BY people.name;
+		`, movies[i].ID); err != nil {
+			return nil, err
+		}
+
+		if len(directors) > 0 {
+			movies[i].Directors = directors
+		}
+	}
+
+	return movies, nil
+}
+
+func (r Repository) QueryListJSON(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	var rows []struct {
+		ID        int64          `db:"id"`
+		Title     string         `db:"title"`
+		AddedAt   time.Time      `db:"added_at"`
+		Rating    float64        `db:"rating"`
+		Directors types.JSONText `db:"directors"`
+	}
+
+	err := r.DB.SelectContext(ctx, &rows, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, COALESCE(d.directors, '[]') AS directors
+		FROM movies m
+		LEFT JOIN LATERAL (
+			SELECT json_agg(json_build_object('id', p.id, 'name', p.name) ORDER BY p.name) AS directors
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+		) d ON true
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3::NUMERIC = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`, params.Search, params.YearAdded, params.MinRating, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]benchflix.Movie, len(rows))
+
+	for i, m := range rows {
+		var people []benchflix.Person
+
+		if err := m.Directors.Unmarshal(&people); err != nil {
+			return nil, err
+		}
+
+		result[i] = benchflix.Movie{
+			ID:      m.ID,
+			Title:   m.Title,
+			AddedAt: m.AddedAt,
+			Rating:  m.Rating,
+		}
+
+		if len(people) > 0 {
+			result[i].Directors = make([]string, len(people))
+
+			for j, p := range people {
+				result[i].Directors[j] = p.Name
+			}
+		}
+	}
+
+	return result, nil
+}
+
 func (r Repository) QueryDashboard(ctx context.Context, params benchflix.DashboardParams) ([]benchflix.Movie, error) {
 	var (
 		sb     = &strings.Builder{}
@@ -382,6 +544,19 @@ func (r Repository) QueryDashboardPreload(ctx context.Context, params benchflix.
 		return movies, nil
 	}
 
+	if r.Loader != nil {
+		directors, err := r.Loader.LoadMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, d := range directors {
+			movies[idMap[id]].Directors = d
+		}
+
+		return movies, nil
+	}
+
 	dirRows, err := r.DB.QueryContext(ctx, `
 		SELECT md.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
 		FROM movie_directors md
@@ -414,3 +589,797 @@ func (r Repository) QueryDashboardPreload(ctx context.Context, params benchflix.
 
 	return movies, nil
 }
+
+func (r Repository) QueryDetails(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	var rows []struct {
+		ID       int64           `db:"id"`
+		Title    string          `db:"title"`
+		AddedAt  time.Time       `db:"added_at"`
+		Rating   float64         `db:"rating"`
+		Runtime  *int64          `db:"runtime"`
+		Budget   *int64          `db:"budget"`
+		Tagline  *string         `db:"tagline"`
+		Metadata *types.JSONText `db:"metadata"`
+	}
+
+	err := r.DB.SelectContext(ctx, &rows, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, m.runtime
+			, m.budget
+			, m.tagline
+			, m.metadata
+		FROM movies m
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3::NUMERIC = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`, params.Search, params.YearAdded, params.MinRating, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]benchflix.Movie, len(rows))
+
+	for i, m := range rows {
+		result[i] = benchflix.Movie{
+			ID:      m.ID,
+			Title:   m.Title,
+			AddedAt: m.AddedAt,
+			Rating:  m.Rating,
+			Runtime: m.Runtime,
+			Budget:  m.Budget,
+			Tagline: m.Tagline,
+		}
+
+		if m.Metadata != nil {
+			result[i].Metadata = &benchflix.Metadata{}
+
+			if err = m.Metadata.Unmarshal(result[i].Metadata); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r Repository) QueryDetailsNull(ctx context.Context, params benchflix.ListParams) ([]benchflix.Movie, error) {
+	var rows []struct {
+		ID       int64              `db:"id"`
+		Title    string             `db:"title"`
+		AddedAt  time.Time          `db:"added_at"`
+		Rating   float64            `db:"rating"`
+		Runtime  sql.NullInt64      `db:"runtime"`
+		Budget   sql.NullInt64      `db:"budget"`
+		Tagline  sql.NullString     `db:"tagline"`
+		Metadata types.NullJSONText `db:"metadata"`
+	}
+
+	err := r.DB.SelectContext(ctx, &rows, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, m.runtime
+			, m.budget
+			, m.tagline
+			, m.metadata
+		FROM movies m
+		WHERE
+			(
+				$1 = ''
+				OR to_tsvector('simple', m.title) @@ plainto_tsquery('simple', $1)
+				OR EXISTS (
+					SELECT 1
+					FROM movie_directors md
+					JOIN people p ON p.id = md.person_id
+					WHERE md.movie_id = m.id
+					AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', $1)
+				)
+			)
+			AND ($2 = 0 OR EXTRACT(YEAR FROM m.added_at) = $2)
+			AND ($3::NUMERIC = 0 OR m.rating >= $3)
+		ORDER BY m.rating DESC
+		LIMIT CASE WHEN $4 BETWEEN 1 AND 1000 THEN $4 ELSE 1000 END;
+	`, params.Search, params.YearAdded, params.MinRating, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]benchflix.Movie, len(rows))
+
+	for i, m := range rows {
+		result[i] = benchflix.Movie{
+			ID:      m.ID,
+			Title:   m.Title,
+			AddedAt: m.AddedAt,
+			Rating:  m.Rating,
+		}
+
+		if m.Runtime.Valid {
+			result[i].Runtime = &m.Runtime.Int64
+		}
+
+		if m.Budget.Valid {
+			result[i].Budget = &m.Budget.Int64
+		}
+
+		if m.Tagline.Valid {
+			result[i].Tagline = &m.Tagline.String
+		}
+
+		if m.Metadata.Valid {
+			result[i].Metadata = &benchflix.Metadata{}
+
+			if err = m.Metadata.Unmarshal(result[i].Metadata); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r Repository) QuerySearch(ctx context.Context, params benchflix.SearchParams) ([]benchflix.SearchResult, error) {
+	var rows []struct {
+		ID        int64          `db:"id"`
+		Title     string         `db:"title"`
+		AddedAt   time.Time      `db:"added_at"`
+		Rating    float64        `db:"rating"`
+		Directors pq.StringArray `db:"directors"`
+		Rank      float64        `db:"rank"`
+		Headline  string         `db:"headline"`
+	}
+
+	tsquery := "plainto_tsquery"
+
+	if params.Websearch {
+		tsquery = "websearch_to_tsquery"
+	}
+
+	err := r.DB.SelectContext(ctx, &rows, fmt.Sprintf(`
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, d.directors
+			, ts_rank(
+				setweight(to_tsvector('simple', m.title), 'A')
+				|| setweight(to_tsvector('simple', COALESCE(d.names, '')), 'B'),
+				q.query
+			) AS rank
+			, ts_headline('simple', m.title || ' / ' || COALESCE(d.names, ''), q.query) AS headline
+		FROM movies m
+		CROSS JOIN %s('simple', $1) AS q (query)
+		LEFT JOIN LATERAL (
+			SELECT
+				ARRAY_AGG(p.name ORDER BY p.name) AS directors
+				, STRING_AGG(p.name, ' ' ORDER BY p.name) AS names
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+		) d ON true
+		WHERE
+			to_tsvector('simple', m.title) @@ q.query
+			OR EXISTS (
+				SELECT 1
+				FROM movie_directors md
+				JOIN people p ON p.id = md.person_id
+				WHERE md.movie_id = m.id
+				AND to_tsvector('simple', p.name) @@ q.query
+			)
+		ORDER BY rank DESC, m.id
+		LIMIT CASE WHEN $2 BETWEEN 1 AND 1000 THEN $2 ELSE 1000 END;
+	`, tsquery), params.Search, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var results = make([]benchflix.SearchResult, len(rows))
+
+	for i, s := range rows {
+		results[i] = benchflix.SearchResult{
+			Movie: benchflix.Movie{
+				ID:        s.ID,
+				Title:     s.Title,
+				AddedAt:   s.AddedAt,
+				Rating:    s.Rating,
+				Directors: s.Directors,
+			},
+			Rank:     s.Rank,
+			Headline: s.Headline,
+		}
+	}
+
+	return results, nil
+}
+
+func (r Repository) QueryFacets(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	var (
+		result benchflix.FacetResult
+		sb     = &strings.Builder{}
+		rows   []struct {
+			Facet string `db:"facet"`
+			Value int64  `db:"value"`
+			Count int64  `db:"count"`
+		}
+		err error
+	)
+
+	result.Movies, err = r.QueryDashboardPreload(ctx, params)
+	if err != nil {
+		return result, err
+	}
+
+	sb.WriteString(`
+		SELECT
+			CASE WHEN GROUPING(EXTRACT(YEAR FROM m.added_at)) = 0 THEN 'year' ELSE 'rating' END AS facet
+			, CAST(COALESCE(EXTRACT(YEAR FROM m.added_at), FLOOR(m.rating)) AS INT8) AS value
+			, COUNT(*) AS count
+		FROM movies m
+		WHERE 1=1`)
+
+	if params.Search != "" {
+		sb.WriteString(` AND (
+			to_tsvector('simple', m.title) @@ plainto_tsquery('simple', :search)
+			OR EXISTS (
+			SELECT 1 FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+				AND to_tsvector('simple', p.name) @@ plainto_tsquery('simple', :search)
+			)
+		)`)
+	}
+	if params.YearAdded != 0 {
+		sb.WriteString(" AND EXTRACT(YEAR FROM m.added_at) = :year_added")
+	}
+	if params.MinRating != 0 {
+		sb.WriteString(" AND m.rating >= :min_rating")
+	}
+
+	sb.WriteString(" GROUP BY GROUPING SETS ((EXTRACT(YEAR FROM m.added_at)), (FLOOR(m.rating))) ORDER BY facet, value")
+
+	sql, args, err := r.DB.BindNamed(sb.String(), params)
+	if err != nil {
+		return result, err
+	}
+
+	if err = r.DB.SelectContext(ctx, &rows, sql, args...); err != nil {
+		return result, err
+	}
+
+	for _, row := range rows {
+		f := benchflix.Facet{Value: row.Value, Count: row.Count}
+
+		if row.Facet == "year" {
+			result.Years = append(result.Years, f)
+		} else {
+			result.Ratings = append(result.Ratings, f)
+		}
+	}
+
+	return result, nil
+}
+
+func (r Repository) QueryFacetsBatch(ctx context.Context, params benchflix.DashboardParams) (benchflix.FacetResult, error) {
+	return benchflix.FacetResult{}, benchflix.ErrSkip
+}
+
+func (r Repository) QueryTopRated(ctx context.Context, params benchflix.TopParams) ([]benchflix.TopMovie, error) {
+	var rows []struct {
+		ID        int64           `db:"id"`
+		Title     string          `db:"title"`
+		AddedAt   time.Time       `db:"added_at"`
+		Rating    float64         `db:"rating"`
+		Year      int64           `db:"year"`
+		Position  int64           `db:"position"`
+		Directors pq.StringArray  `db:"directors"`
+		Ratings   pq.Float64Array `db:"ratings"`
+	}
+
+	err := r.DB.SelectContext(ctx, &rows, `
+		WITH director_ratings AS (
+			SELECT
+				md.person_id
+				, CAST(AVG(m.rating) AS FLOAT8) AS avg_rating
+			FROM movie_directors md
+			JOIN movies m ON m.id = md.movie_id
+			GROUP BY md.person_id
+		), ranked AS (
+			SELECT
+				m.id
+				, m.title
+				, m.added_at
+				, m.rating
+				, CAST(EXTRACT(YEAR FROM m.added_at) AS INT8) AS year
+				, ROW_NUMBER() OVER (PARTITION BY EXTRACT(YEAR FROM m.added_at) ORDER BY m.rating DESC, m.id) AS position
+			FROM movies m
+			WHERE $1::NUMERIC = 0 OR m.rating >= $1
+		)
+		SELECT
+			r.id
+			, r.title
+			, r.added_at
+			, r.rating
+			, r.year
+			, r.position
+			, d.directors
+			, d.ratings
+		FROM ranked r
+		LEFT JOIN LATERAL (
+			SELECT
+				ARRAY_AGG(p.name ORDER BY p.name) AS directors
+				, ARRAY_AGG(dr.avg_rating ORDER BY p.name) AS ratings
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			JOIN director_ratings dr ON dr.person_id = md.person_id
+			WHERE md.movie_id = r.id
+		) d ON true
+		WHERE r.position <= CASE WHEN $2 BETWEEN 1 AND 10 THEN $2 ELSE 10 END
+		ORDER BY r.year, r.position;
+	`, params.MinRating, params.PerYear)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]benchflix.TopMovie, len(rows))
+
+	for i, m := range rows {
+		result[i] = benchflix.TopMovie{
+			Movie: benchflix.Movie{
+				ID:        m.ID,
+				Title:     m.Title,
+				AddedAt:   m.AddedAt,
+				Rating:    m.Rating,
+				Directors: m.Directors,
+			},
+			Year:            m.Year,
+			Position:        m.Position,
+			DirectorRatings: m.Ratings,
+		}
+	}
+
+	return result, nil
+}
+
+func (r Repository) QueryCollaborators(ctx context.Context, params benchflix.GraphParams) ([]benchflix.Collaborator, error) {
+	var rows []struct {
+		PersonID int64          `db:"person_id"`
+		Name     string         `db:"name"`
+		Depth    int64          `db:"depth"`
+		Movies   pq.StringArray `db:"movies"`
+	}
+
+	err := r.DB.SelectContext(ctx, &rows, `
+		WITH RECURSIVE graph (person_id, depth, path, movies) AS (
+			SELECT
+				CAST(p.id AS INT8)
+				, CAST(0 AS INT8)
+				, ARRAY[CAST(p.id AS INT8)]
+				, CAST(ARRAY[] AS TEXT[])
+			FROM people p
+			WHERE p.name = $1
+			UNION ALL
+			SELECT
+				CAST(b.person_id AS INT8)
+				, g.depth + 1
+				, g.path || CAST(b.person_id AS INT8)
+				, g.movies || m.title
+			FROM graph g
+			JOIN movie_directors a ON a.person_id = g.person_id
+			JOIN movie_directors b ON b.movie_id = a.movie_id
+			JOIN movies m ON m.id = a.movie_id
+			WHERE g.depth < CASE WHEN $2 BETWEEN 1 AND 3 THEN $2 ELSE 3 END
+			AND b.person_id <> ALL (g.path)
+		)
+		SELECT
+			c.person_id
+			, p.name
+			, c.depth
+			, c.movies
+		FROM (
+			SELECT DISTINCT ON (g.person_id)
+				g.person_id
+				, g.depth
+				, g.movies
+			FROM graph g
+			WHERE g.depth > 0
+			ORDER BY g.person_id, g.depth, g.movies
+		) c
+		JOIN people p ON p.id = c.person_id
+		ORDER BY c.depth, p.name;
+	`, params.Director, params.Depth)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]benchflix.Collaborator, len(rows))
+
+	for i, c := range rows {
+		result[i] = benchflix.Collaborator{
+			ID:     c.PersonID,
+			Name:   c.Name,
+			Depth:  c.Depth,
+			Movies: c.Movies,
+		}
+	}
+
+	return result, nil
+}
+
+func (r Repository) QueryMovie(ctx context.Context, id int64) (benchflix.Movie, error) {
+	var row struct {
+		ID        int64          `db:"id"`
+		Title     string         `db:"title"`
+		AddedAt   time.Time      `db:"added_at"`
+		Rating    float64        `db:"rating"`
+		Directors pq.StringArray `db:"directors"`
+	}
+
+	err := r.DB.GetContext(ctx, &row, `
+		SELECT
+			m.id
+			, m.title
+			, m.added_at
+			, m.rating
+			, d.directors
+		FROM movies m
+		LEFT JOIN LATERAL (
+			SELECT ARRAY_AGG(p.name ORDER BY p.name) AS directors
+			FROM movie_directors md
+			JOIN people p ON p.id = md.person_id
+			WHERE md.movie_id = m.id
+		) d ON true
+		WHERE m.id = $1;
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return benchflix.Movie{}, benchflix.ErrNotFound
+	}
+
+	if err != nil {
+		return benchflix.Movie{}, err
+	}
+
+	return benchflix.Movie{
+		ID:        row.ID,
+		Title:     row.Title,
+		AddedAt:   row.AddedAt,
+		Rating:    row.Rating,
+		Directors: row.Directors,
+	}, nil
+}
+
+func (r Repository) QueryWide(ctx context.Context, params benchflix.WideParams) ([]benchflix.WideRow, error) {
+	var rows []struct {
+		ID     int64          `db:"id"`
+		Int01  int64          `db:"int01"`
+		Int02  int64          `db:"int02"`
+		Int03  int64          `db:"int03"`
+		Int04  int64          `db:"int04"`
+		Int05  int64          `db:"int05"`
+		Int06  int64          `db:"int06"`
+		Int07  int64          `db:"int07"`
+		Int08  int64          `db:"int08"`
+		Int09  int64          `db:"int09"`
+		Int10  int64          `db:"int10"`
+		Int11  int64          `db:"int11"`
+		Int12  int64          `db:"int12"`
+		Num01  float64        `db:"num01"`
+		Num02  float64        `db:"num02"`
+		Num03  float64        `db:"num03"`
+		Num04  float64        `db:"num04"`
+		Num05  float64        `db:"num05"`
+		Num06  float64        `db:"num06"`
+		Num07  float64        `db:"num07"`
+		Num08  float64        `db:"num08"`
+		Num09  float64        `db:"num09"`
+		Num10  float64        `db:"num10"`
+		Num11  float64        `db:"num11"`
+		Num12  float64        `db:"num12"`
+		Time01 time.Time      `db:"time01"`
+		Time02 time.Time      `db:"time02"`
+		Time03 time.Time      `db:"time03"`
+		Time04 time.Time      `db:"time04"`
+		Time05 time.Time      `db:"time05"`
+		Time06 time.Time      `db:"time06"`
+		Time07 time.Time      `db:"time07"`
+		Time08 time.Time      `db:"time08"`
+		Time09 time.Time      `db:"time09"`
+		Time10 time.Time      `db:"time10"`
+		Time11 time.Time      `db:"time11"`
+		Time12 time.Time      `db:"time12"`
+		Tags01 pq.StringArray `db:"tags01"`
+		Tags02 pq.StringArray `db:"tags02"`
+		Tags03 pq.StringArray `db:"tags03"`
+		Tags04 pq.StringArray `db:"tags04"`
+		Tags05 pq.StringArray `db:"tags05"`
+		Tags06 pq.StringArray `db:"tags06"`
+		Tags07 pq.StringArray `db:"tags07"`
+		Tags08 pq.StringArray `db:"tags08"`
+		Tags09 pq.StringArray `db:"tags09"`
+		Tags10 pq.StringArray `db:"tags10"`
+		Tags11 pq.StringArray `db:"tags11"`
+		Tags12 pq.StringArray `db:"tags12"`
+		Note01 *string        `db:"note01"`
+		Note02 *string        `db:"note02"`
+		Note03 *string        `db:"note03"`
+		Note04 *string        `db:"note04"`
+		Note05 *string        `db:"note05"`
+		Note06 *string        `db:"note06"`
+		Note07 *string        `db:"note07"`
+		Note08 *string        `db:"note08"`
+		Note09 *string        `db:"note09"`
+		Note10 *string        `db:"note10"`
+		Note11 *string        `db:"note11"`
+		Note12 *string        `db:"note12"`
+	}
+
+	err := r.DB.SelectContext(ctx, &rows, `
+		SELECT
+			id
+			, int01
+			, int02
+			, int03
+			, int04
+			, int05
+			, int06
+			, int07
+			, int08
+			, int09
+			, int10
+			, int11
+			, int12
+			, num01
+			, num02
+			, num03
+			, num04
+			, num05
+			, num06
+			, num07
+			, num08
+			, num09
+			, num10
+			, num11
+			, num12
+			, time01
+			, time02
+			, time03
+			, time04
+			, time05
+			, time06
+			, time07
+			, time08
+			, time09
+			, time10
+			, time11
+			, time12
+			, tags01
+			, tags02
+			, tags03
+			, tags04
+			, tags05
+			, tags06
+			, tags07
+			, tags08
+			, tags09
+			, tags10
+			, tags11
+			, tags12
+			, note01
+			, note02
+			, note03
+			, note04
+			, note05
+			, note06
+			, note07
+			, note08
+			, note09
+			, note10
+			, note11
+			, note12
+		FROM wide_rows
+		WHERE id > $1
+		ORDER BY id
+		LIMIT CASE WHEN $2 BETWEEN 1 AND 1000 THEN $2 ELSE 1000 END;
+	`, params.After, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]benchflix.WideRow, len(rows))
+
+	for i, w := range rows {
+		result[i] = benchflix.WideRow{
+			ID:     w.ID,
+			Int01:  w.Int01,
+			Int02:  w.Int02,
+			Int03:  w.Int03,
+			Int04:  w.Int04,
+			Int05:  w.Int05,
+			Int06:  w.Int06,
+			Int07:  w.Int07,
+			Int08:  w.Int08,
+			Int09:  w.Int09,
+			Int10:  w.Int10,
+			Int11:  w.Int11,
+			Int12:  w.Int12,
+			Num01:  w.Num01,
+			Num02:  w.Num02,
+			Num03:  w.Num03,
+			Num04:  w.Num04,
+			Num05:  w.Num05,
+			Num06:  w.Num06,
+			Num07:  w.Num07,
+			Num08:  w.Num08,
+			Num09:  w.Num09,
+			Num10:  w.Num10,
+			Num11:  w.Num11,
+			Num12:  w.Num12,
+			Time01: w.Time01,
+			Time02: w.Time02,
+			Time03: w.Time03,
+			Time04: w.Time04,
+			Time05: w.Time05,
+			Time06: w.Time06,
+			Time07: w.Time07,
+			Time08: w.Time08,
+			Time09: w.Time09,
+			Time10: w.Time10,
+			Time11: w.Time11,
+			Time12: w.Time12,
+			Tags01: w.Tags01,
+			Tags02: w.Tags02,
+			Tags03: w.Tags03,
+			Tags04: w.Tags04,
+			Tags05: w.Tags05,
+			Tags06: w.Tags06,
+			Tags07: w.Tags07,
+			Tags08: w.Tags08,
+			Tags09: w.Tags09,
+			Tags10: w.Tags10,
+			Tags11: w.Tags11,
+			Tags12: w.Tags12,
+			Note01: w.Note01,
+			Note02: w.Note02,
+			Note03: w.Note03,
+			Note04: w.Note04,
+			Note05: w.Note05,
+			Note06: w.Note06,
+			Note07: w.Note07,
+			Note08: w.Note08,
+			Note09: w.Note09,
+			Note10: w.Note10,
+			Note11: w.Note11,
+			Note12: w.Note12,
+		}
+	}
+
+	return result, nil
+}
+
+func (r Repository) QueryDirectors(ctx context.Context, params benchflix.PreloadParams) ([]benchflix.MovieDirectors, error) {
+	var rows []struct {
+		MovieID   int64          `db:"movie_id"`
+		Directors pq.StringArray `db:"directors"`
+	}
+
+	switch params.Strategy {
+	case benchflix.PreloadAny:
+		if err := r.DB.SelectContext(ctx, &rows, `
+			SELECT md.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+			FROM movie_directors md
+			JOIN people ON people.id = md.person_id
+			WHERE md.movie_id = ANY ($1)
+			GROUP BY md.movie_id;
+		`, pq.Int64Array(params.IDs)); err != nil {
+			return nil, err
+		}
+	case benchflix.PreloadUnnest:
+		if err := r.DB.SelectContext(ctx, &rows, `
+			SELECT i.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+			FROM unnest($1::INT8[]) WITH ORDINALITY AS i (movie_id, ord)
+			JOIN movie_directors md ON md.movie_id = i.movie_id
+			JOIN people ON people.id = md.person_id
+			GROUP BY i.ord, i.movie_id
+			ORDER BY i.ord;
+		`, pq.Int64Array(params.IDs)); err != nil {
+			return nil, err
+		}
+	case benchflix.PreloadTempTable:
+		tx, err := r.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() { _ = tx.Rollback() }()
+
+		if _, err = tx.ExecContext(ctx, `CREATE TEMP TABLE preload_ids (ord INT8, movie_id INT8) ON COMMIT DROP;`); err != nil {
+			return nil, err
+		}
+
+		stmt, err := tx.PrepareContext(ctx, pq.CopyIn("preload_ids", "ord", "movie_id"))
+		if err != nil {
+			return nil, err
+		}
+
+		for i, id := range params.IDs {
+			if _, err = stmt.ExecContext(ctx, int64(i+1), id); err != nil {
+				_ = stmt.Close()
+
+				return nil, err
+			}
+		}
+
+		if _, err = stmt.ExecContext(ctx); err != nil {
+			_ = stmt.Close()
+
+			return nil, err
+		}
+
+		if err = stmt.Close(); err != nil {
+			return nil, err
+		}
+
+		if err = tx.SelectContext(ctx, &rows, `
+			SELECT i.movie_id, ARRAY_AGG(people.name ORDER BY people.name) AS directors
+			FROM preload_ids i
+			JOIN movie_directors md ON md.movie_id = i.movie_id
+			JOIN people ON people.id = md.person_id
+			GROUP BY i.ord, i.movie_id
+			ORDER BY i.ord;
+		`); err != nil {
+			return nil, err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return nil, err
+		}
+	case benchflix.PreloadBatch:
+		return nil, benchflix.ErrSkip
+	default:
+		return nil, fmt.Errorf("invalid strategy: %s", params.Strategy)
+	}
+
+	var result = make([]benchflix.MovieDirectors, len(rows))
+
+	for i, md := range rows {
+		result[i] = benchflix.MovieDirectors{
+			MovieID:   md.MovieID,
+			Directors: md.Directors,
+		}
+	}
+
+	return result, nil
+}
+
+func (r Repository) WithLoader(wait time.Duration, maxBatch int) benchflix.Repository {
+	r.Loader = benchflix.NewDirectorsLoader(wait, maxBatch, r.QueryDirectors)
+
+	return r
+}
+
+func (r Repository) PoolStats() benchflix.PoolStats {
+	return benchflix.SQLPoolStats(r.DB.DB)
+}
+
+func (r Repository) Close() error {
+	return r.DB.Close()
+}
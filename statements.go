@@ -0,0 +1,33 @@
+package benchflix
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+type statementKey struct{}
+
+// CaptureStatements returns a context under which repositories opened
+// WithInstrumentation pass every statement they send, with its arguments, to
+// fn.
+func CaptureStatements(ctx context.Context, fn func(sql string, args []any)) context.Context {
+	return context.WithValue(ctx, statementKey{}, fn)
+}
+
+func captureStatement(ctx context.Context, sql string, args []any) {
+	if fn, ok := ctx.Value(statementKey{}).(func(string, []any)); ok {
+		fn(sql, args)
+	}
+}
+
+func captureNamedStatement(ctx context.Context, sql string, args []driver.NamedValue) {
+	if fn, ok := ctx.Value(statementKey{}).(func(string, []any)); ok {
+		values := make([]any, len(args))
+
+		for i, arg := range args {
+			values[i] = arg.Value
+		}
+
+		fn(sql, values)
+	}
+}
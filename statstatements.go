@@ -0,0 +1,151 @@
+package benchflix
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StatementStats is a row of pg_stat_statements. Times are in milliseconds.
+type StatementStats struct {
+	Query          string  `json:"query"`
+	Calls          int64   `json:"calls"`
+	Plans          int64   `json:"plans"`
+	Rows           int64   `json:"rows"`
+	TotalExecTime  float64 `json:"total_exec_time"`
+	MeanExecTime   float64 `json:"mean_exec_time"`
+	TotalPlanTime  float64 `json:"total_plan_time"`
+	MeanPlanTime   float64 `json:"mean_plan_time"`
+	SharedBlksHit  int64   `json:"shared_blks_hit"`
+	SharedBlksRead int64   `json:"shared_blks_read"`
+}
+
+// SnapshotStatementStats returns the statistics of all statements run against
+// the current database, most expensive first. Subtract an earlier snapshot with
+// DiffStatementStats to get the statements run in between.
+func SnapshotStatementStats(ctx context.Context, db *pgxpool.Pool) ([]StatementStats, error) {
+	rows, err := db.Query(ctx, `
+		SELECT
+			query
+			, calls
+			, plans
+			, rows
+			, total_exec_time
+			, mean_exec_time
+			, total_plan_time
+			, mean_plan_time
+			, shared_blks_hit
+			, shared_blks_read
+		FROM pg_stat_statements
+		WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+		AND query NOT LIKE '%pg_stat_statements%'
+		ORDER BY total_exec_time DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var stats []StatementStats
+
+	for rows.Next() {
+		var s StatementStats
+
+		if err = rows.Scan(&s.Query, &s.Calls, &s.Plans, &s.Rows, &s.TotalExecTime, &s.MeanExecTime, &s.TotalPlanTime, &s.MeanPlanTime, &s.SharedBlksHit, &s.SharedBlksRead); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+// DiffStatementStats returns the statistics of the statements run between the
+// snapshots before and after, most expensive first. Rows of the same query are
+// merged and statements without calls in between are dropped.
+func DiffStatementStats(before, after []StatementStats) []StatementStats {
+	previous := make(map[string]StatementStats, len(before))
+
+	for _, s := range before {
+		previous[s.Query] = SumStatementStats([]StatementStats{previous[s.Query], s})
+	}
+
+	var (
+		stats []StatementStats
+		index = make(map[string]int, len(after))
+	)
+
+	for _, s := range after {
+		if i, ok := index[s.Query]; ok {
+			stats[i] = SumStatementStats([]StatementStats{stats[i], s})
+
+			continue
+		}
+
+		index[s.Query] = len(stats)
+		stats = append(stats, s)
+	}
+
+	diff := stats[:0]
+
+	for _, s := range stats {
+		p := previous[s.Query]
+
+		s.Calls -= p.Calls
+		s.Plans -= p.Plans
+		s.Rows -= p.Rows
+		s.TotalExecTime -= p.TotalExecTime
+		s.TotalPlanTime -= p.TotalPlanTime
+		s.SharedBlksHit -= p.SharedBlksHit
+		s.SharedBlksRead -= p.SharedBlksRead
+
+		if s.Calls <= 0 {
+			continue
+		}
+
+		s.MeanExecTime, s.MeanPlanTime = mean(s.TotalExecTime, s.Calls), mean(s.TotalPlanTime, s.Plans)
+
+		diff = append(diff, s)
+	}
+
+	slices.SortStableFunc(diff, func(a, b StatementStats) int {
+		return cmp.Compare(b.TotalExecTime, a.TotalExecTime)
+	})
+
+	return diff
+}
+
+// SumStatementStats adds up stats, the query is the one of the first statement.
+func SumStatementStats(stats []StatementStats) StatementStats {
+	var sum StatementStats
+
+	for _, s := range stats {
+		if sum.Query == "" {
+			sum.Query = s.Query
+		}
+
+		sum.Calls += s.Calls
+		sum.Plans += s.Plans
+		sum.Rows += s.Rows
+		sum.TotalExecTime += s.TotalExecTime
+		sum.TotalPlanTime += s.TotalPlanTime
+		sum.SharedBlksHit += s.SharedBlksHit
+		sum.SharedBlksRead += s.SharedBlksRead
+	}
+
+	sum.MeanExecTime, sum.MeanPlanTime = mean(sum.TotalExecTime, sum.Calls), mean(sum.TotalPlanTime, sum.Plans)
+
+	return sum
+}
+
+func mean(total float64, n int64) float64 {
+	if n == 0 {
+		return 0
+	}
+
+	return total / float64(n)
+}
@@ -0,0 +1,152 @@
+package benchflix
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Tracer writes a span per repository call to w, one OTLP/JSON
+// ExportTraceServiceRequest per line as written by the OpenTelemetry
+// collector's file exporter. Calls made within a traced call become its
+// children. Skipped calls keep the unset status code.
+type Tracer struct {
+	service string
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func NewTracer(w io.Writer, service string) *Tracer {
+	return &Tracer{
+		service: service,
+		enc:     json.NewEncoder(w),
+	}
+}
+
+type spanContextKey struct{}
+
+type spanContext struct {
+	traceID, spanID string
+}
+
+// Middleware traces every call of the wrapped repository.
+func (t *Tracer) Middleware(repo Repository) Repository {
+	return Intercept(func(ctx context.Context, call Call, next func(context.Context) (any, error)) (any, error) {
+		span := otlpSpan{
+			SpanID:            newID(8),
+			Name:              call.Method,
+			Kind:              3, // SPAN_KIND_CLIENT
+			StartTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		}
+
+		if parent, ok := ctx.Value(spanContextKey{}).(spanContext); ok {
+			span.TraceID, span.ParentSpanID = parent.traceID, parent.spanID
+		} else {
+			span.TraceID = newID(16)
+		}
+
+		result, err := next(context.WithValue(ctx, spanContextKey{}, spanContext{traceID: span.TraceID, spanID: span.SpanID}))
+
+		span.EndTimeUnixNano = strconv.FormatInt(time.Now().UnixNano(), 10)
+		span.Attributes = []otlpAttribute{
+			{Key: "benchflix.params", Value: otlpValue{StringValue: fmt.Sprintf("%+v", call.Params)}},
+			{Key: "benchflix.results", Value: otlpValue{IntValue: strconv.Itoa(ResultSize(result))}},
+			{Key: "benchflix.status", Value: otlpValue{StringValue: Status(err)}},
+		}
+
+		switch Status(err) {
+		case "ok":
+			span.Status.Code = 1 // STATUS_CODE_OK
+		case "error":
+			span.Status.Code = 2 // STATUS_CODE_ERROR
+			span.Status.Message = err.Error()
+		}
+
+		t.write(span)
+
+		return result, err
+	})(repo)
+}
+
+func (t *Tracer) write(span otlpSpan) {
+	request := otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpAttribute{{Key: "service.name", Value: otlpValue{StringValue: t.service}}},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/go-sqlt/benchflix"},
+				Spans: []otlpSpan{span},
+			}},
+		}},
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_ = t.enc.Encode(request)
+}
+
+func newID(size int) string {
+	id := make([]byte, size)
+
+	for i := range id {
+		id[i] = byte(rand.Uint32())
+	}
+
+	return hex.EncodeToString(id)
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+	Status            struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	} `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue,omitempty"`
+	IntValue    string `json:"intValue,omitempty"`
+}
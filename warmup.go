@@ -0,0 +1,262 @@
+package benchflix
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type WarmupMode int
+
+const (
+	// WarmupCount runs a fixed number of calls.
+	WarmupCount WarmupMode = iota
+	// WarmupDuration runs calls for a fixed duration.
+	WarmupDuration
+	// WarmupSteady runs calls until the mean latency of the last windows
+	// differs by at most Tolerance, or MaxCalls is reached.
+	WarmupSteady
+)
+
+// Warmup decides how long a benchmark runs before it is measured. Calls run in
+// windows of Window calls, the mean latency of every window makes up the
+// warmup curve. Afterwards the garbage collector runs and the benchmark waits
+// for Settle.
+type Warmup struct {
+	Mode      WarmupMode
+	Count     int
+	Duration  time.Duration
+	Window    int
+	Tolerance float64
+	MaxCalls  int
+	Settle    time.Duration
+}
+
+const (
+	// steadyWindows is the number of consecutive windows that have to agree.
+	steadyWindows = 3
+	// defaultTolerance decides whether a count or duration warmup ended steady.
+	defaultTolerance = 0.05
+)
+
+// ParseWarmup parses count:N, duration:D or steady:WINDOW:TOLERANCE:MAXCALLS.
+func ParseWarmup(spec string) (Warmup, error) {
+	mode, args, _ := strings.Cut(spec, ":")
+	parts := strings.Split(args, ":")
+
+	switch mode {
+	case "count":
+		count, err := strconv.Atoi(args)
+		if err != nil {
+			return Warmup{}, fmt.Errorf("warmup count: %w", err)
+		}
+
+		if count < 0 {
+			return Warmup{}, fmt.Errorf("warmup count: negative %d", count)
+		}
+
+		return Warmup{Mode: WarmupCount, Count: count, Window: 500, Tolerance: defaultTolerance}, nil
+	case "duration":
+		duration, err := time.ParseDuration(args)
+		if err != nil {
+			return Warmup{}, fmt.Errorf("warmup duration: %w", err)
+		}
+
+		if duration < 0 {
+			return Warmup{}, fmt.Errorf("warmup duration: negative %s", duration)
+		}
+
+		return Warmup{Mode: WarmupDuration, Duration: duration, Window: 500, Tolerance: defaultTolerance}, nil
+	case "steady":
+		if len(parts) != 3 {
+			return Warmup{}, fmt.Errorf("warmup steady: want WINDOW:TOLERANCE:MAXCALLS, got %q", args)
+		}
+
+		window, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return Warmup{}, fmt.Errorf("warmup window: %w", err)
+		}
+
+		tolerance, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return Warmup{}, fmt.Errorf("warmup tolerance: %w", err)
+		}
+
+		maxCalls, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return Warmup{}, fmt.Errorf("warmup max calls: %w", err)
+		}
+
+		if window <= 0 {
+			return Warmup{}, fmt.Errorf("warmup window: want positive, got %d", window)
+		}
+
+		if !(tolerance >= 0) {
+			return Warmup{}, fmt.Errorf("warmup tolerance: want at least 0, got %g", tolerance)
+		}
+
+		if maxCalls < 0 {
+			return Warmup{}, fmt.Errorf("warmup max calls: negative %d", maxCalls)
+		}
+
+		return Warmup{Mode: WarmupSteady, Window: window, Tolerance: tolerance, MaxCalls: maxCalls}, nil
+	default:
+		return Warmup{}, fmt.Errorf("invalid warmup: %s", spec)
+	}
+}
+
+// WarmupResult describes a finished warmup. Curve holds the mean latency of
+// every window.
+type WarmupResult struct {
+	Calls    int
+	Duration time.Duration
+	Steady   bool
+	Curve    []time.Duration
+}
+
+// Run warms up with concurrency workers that call one after the other like the
+// measured loop, windows are only accounted, not waited for. call receives the
+// index of the call.
+func (w Warmup) Run(concurrency int, call func(i int) error) (WarmupResult, error) {
+	var (
+		result  WarmupResult
+		start   = time.Now()
+		window  = max(w.Window, 1)
+		limit   = w.limit()
+		next    atomic.Int64
+		stopped atomic.Bool
+		mu      sync.Mutex
+		pending = map[int]*warmupWindow{}
+		group   errgroup.Group
+	)
+
+	// finish accounts a call and appends every window that is complete in order
+	finish := func(i int, latency time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		result.Calls++
+
+		win := pending[i/window]
+		if win == nil {
+			win = &warmupWindow{}
+			pending[i/window] = win
+		}
+
+		win.calls++
+		win.latency += latency
+
+		for {
+			k := len(result.Curve)
+
+			size := window
+			if limit > 0 {
+				size = min(window, limit-k*window)
+			}
+
+			win, ok := pending[k]
+			if !ok || win.calls < size {
+				return
+			}
+
+			delete(pending, k)
+
+			result.Curve = append(result.Curve, win.latency/time.Duration(size))
+
+			if w.done(result, time.Since(start)) {
+				stopped.Store(true)
+			}
+		}
+	}
+
+	if !w.done(result, 0) {
+		for range max(concurrency, 1) {
+			group.Go(func() error {
+				for !stopped.Load() {
+					i := int(next.Add(1) - 1)
+					if limit > 0 && i >= limit {
+						return nil
+					}
+
+					callStart := time.Now()
+
+					if err := call(i); err != nil {
+						stopped.Store(true)
+
+						return err
+					}
+
+					finish(i, time.Since(callStart))
+				}
+
+				return nil
+			})
+		}
+	}
+
+	if err := group.Wait(); err != nil {
+		return result, err
+	}
+
+	result.Duration = time.Since(start)
+	result.Steady = steady(result.Curve, w.Tolerance)
+
+	runtime.GC()
+	time.Sleep(w.Settle)
+
+	return result, nil
+}
+
+type warmupWindow struct {
+	calls   int
+	latency time.Duration
+}
+
+// limit is the number of calls after which no call starts, 0 if there is none.
+func (w Warmup) limit() int {
+	switch w.Mode {
+	case WarmupCount:
+		return w.Count
+	case WarmupSteady:
+		return w.MaxCalls
+	default:
+		return 0
+	}
+}
+
+func (w Warmup) done(result WarmupResult, elapsed time.Duration) bool {
+	switch w.Mode {
+	case WarmupDuration:
+		return elapsed >= w.Duration
+	case WarmupSteady:
+		if result.Calls >= w.MaxCalls {
+			return true
+		}
+
+		return steady(result.Curve, w.Tolerance)
+	default:
+		return result.Calls >= w.Count
+	}
+}
+
+func steady(curve []time.Duration, tolerance float64) bool {
+	if len(curve) < steadyWindows {
+		return false
+	}
+
+	last := curve[len(curve)-steadyWindows:]
+
+	low, high := last[0], last[0]
+
+	for _, d := range last[1:] {
+		low, high = min(low, d), max(high, d)
+	}
+
+	return low > 0 && float64(high)/float64(low)-1 <= tolerance
+}